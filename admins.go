@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+)
+
+// promoteAdmins gives the admin role to the accounts listed in ADMIN_EMAILS
+// (comma separated), so a fresh deployment has someone who can hand out
+// roles through the API. Addresses without an account, or whose account has
+// not verified the address yet, are skipped: anyone can sign up with an
+// address they do not own.
+func promoteAdmins() {
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		user, err := store.Users.FindByEmail(context.Background(), email)
+		if errors.Is(err, store.ErrNotFound) {
+			log.Printf("⚠️ ADMIN_EMAILS: no account for %s", email)
+			continue
+		}
+		if err != nil {
+			log.Println("FindByEmail error:", err)
+			continue
+		}
+		if user.CurrentRole() == models.RoleAdmin {
+			continue
+		}
+		if !user.EmailVerified {
+			log.Printf("⚠️ ADMIN_EMAILS: %s has not verified the address, not promoting", email)
+			continue
+		}
+		if err := store.Users.SetRole(context.Background(), user.ID, models.RoleAdmin); err != nil {
+			log.Println("SetRole error:", err)
+			continue
+		}
+		log.Printf("Promoted %s to admin", email)
+	}
+}
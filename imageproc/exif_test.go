@@ -0,0 +1,81 @@
+package imageproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// exifJPEG returns the start of a JPEG carrying only an EXIF segment with
+// the given orientation, in the given TIFF byte order.
+func exifJPEG(order string, orientation byte) []byte {
+	tiff := []byte(order)
+	if order == "MM" {
+		tiff = append(tiff, 0, 0x2a, 0, 0, 0, 8, // header, IFD0 at 8
+			0, 1, // one entry
+			0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0,
+			0, 0, 0, 0)
+	} else {
+		tiff = append(tiff, 0x2a, 0, 8, 0, 0, 0,
+			1, 0,
+			0x12, 0x01, 3, 0, 1, 0, 0, 0, orientation, 0, 0, 0,
+			0, 0, 0, 0)
+	}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(length >> 8), byte(length)}
+	data = append(data, segment...)
+	return append(data, 0xFF, 0xDA, 0, 2)
+}
+
+func TestExifOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"big endian", exifJPEG("MM", 6), 6},
+		{"little endian", exifJPEG("II", 8), 8},
+		{"out of range", exifJPEG("MM", 9), 1},
+		{"no exif", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}, 1},
+		{"not a jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"truncated", exifJPEG("MM", 6)[:12], 1},
+	}
+	for _, tt := range tests {
+		if got := exifOrientation(tt.data); got != tt.want {
+			t.Errorf("%s: exifOrientation() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3×2 image with a marked top-left corner
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	red := color.NRGBA{R: 255, A: 255}
+	img.SetNRGBA(0, 0, red)
+
+	tests := []struct {
+		orientation int
+		size        image.Point
+		corner      image.Point
+	}{
+		{1, image.Pt(3, 2), image.Pt(0, 0)},
+		{2, image.Pt(3, 2), image.Pt(2, 0)},
+		{3, image.Pt(3, 2), image.Pt(2, 1)},
+		{4, image.Pt(3, 2), image.Pt(0, 1)},
+		{5, image.Pt(2, 3), image.Pt(0, 0)},
+		{6, image.Pt(2, 3), image.Pt(1, 0)},
+		{7, image.Pt(2, 3), image.Pt(1, 2)},
+		{8, image.Pt(2, 3), image.Pt(0, 2)},
+	}
+	for _, tt := range tests {
+		got := orient(img, tt.orientation)
+		if size := got.Bounds().Size(); size != tt.size {
+			t.Errorf("orientation %d: size = %v, want %v", tt.orientation, size, tt.size)
+			continue
+		}
+		if c := color.NRGBAModel.Convert(got.At(tt.corner.X, tt.corner.Y)); c != red {
+			t.Errorf("orientation %d: marked pixel not at %v", tt.orientation, tt.corner)
+		}
+	}
+}
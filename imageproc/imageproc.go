@@ -0,0 +1,177 @@
+// Package imageproc checks uploaded images and prepares them for storage.
+// Every upload is decoded and encoded again, which drops EXIF and any other
+// metadata (GPS positions in phone photos in particular), and is scaled down
+// into a medium and a thumbnail variant.
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	// Decoders for the accepted formats
+	_ "image/gif"
+
+	_ "golang.org/x/image/webp"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// MaxBytes is the largest upload accepted.
+	MaxBytes = 10 << 20
+	// MaxDimension is the largest accepted width or height in pixels.
+	MaxDimension = 8000
+	// MaxPixels caps width × height, since a small file can still decode
+	// to an enormous bitmap.
+	MaxPixels = 40_000_000
+	// MinDimension is the smallest accepted width or height in pixels.
+	MinDimension = 16
+
+	// MediumSize and ThumbnailSize bound the longer side of the variants.
+	MediumSize    = 1024
+	ThumbnailSize = 320
+
+	jpegQuality = 85
+)
+
+var (
+	ErrTooLarge        = errors.New("image is larger than 10 MB")
+	ErrUnsupportedType = errors.New("image must be a JPEG, PNG, GIF or WebP file")
+	ErrDimensions      = errors.New("image dimensions are out of range")
+	ErrCorrupt         = errors.New("image could not be decoded")
+)
+
+// acceptedTypes are the sniffed content types Process accepts.
+var acceptedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Variant is one encoded version of an image.
+type Variant struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Ext is the file extension matching the variant's content type.
+func (v Variant) Ext() string {
+	if v.ContentType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// Result holds the re-encoded original, its smaller variants and its
+// perceptual hash.
+type Result struct {
+	Original  Variant
+	Medium    Variant
+	Thumbnail Variant
+	Hash      Hash
+}
+
+// Process reads an uploaded image, checks it, and returns clean copies at
+// full, medium and thumbnail size, along with its perceptual hash. Images
+// with transparency are encoded as PNG, everything else as JPEG. Animated
+// GIFs keep their first frame.
+func Process(r io.Reader) (*Result, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	// Trust the bytes, not the file name or the client's Content-Type
+	if !acceptedTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedType
+	}
+
+	// Check the size from the header before decoding the pixels
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	if config.Width < MinDimension || config.Height < MinDimension ||
+		config.Width > MaxDimension || config.Height > MaxDimension ||
+		config.Width*config.Height > MaxPixels {
+		return nil, ErrDimensions
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+
+	// The orientation lives in the EXIF data we are about to drop, so bake
+	// it into the pixels
+	img := orient(src, exifOrientation(data))
+
+	opaque := true
+	if o, ok := src.(interface{ Opaque() bool }); ok {
+		opaque = o.Opaque()
+	}
+
+	var result Result
+	if result.Original, err = encode(img, opaque); err != nil {
+		return nil, err
+	}
+	if result.Medium, err = encode(fit(img, MediumSize), opaque); err != nil {
+		return nil, err
+	}
+	thumbnail := fit(img, ThumbnailSize)
+	if result.Thumbnail, err = encode(thumbnail, opaque); err != nil {
+		return nil, err
+	}
+	// The thumbnail has more than enough detail for the hash and is much
+	// cheaper to sample than the original
+	result.Hash = PerceptualHash(thumbnail)
+	return &result, nil
+}
+
+// fit scales img down so neither side exceeds size. Smaller images are
+// returned unchanged.
+func fit(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+	if w >= h {
+		h = max(1, h*size/w)
+		w = size
+	} else {
+		w = max(1, w*size/h)
+		h = size
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+func encode(img image.Image, opaque bool) (Variant, error) {
+	var buf bytes.Buffer
+	v := Variant{ContentType: "image/jpeg", Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	if opaque {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return Variant{}, err
+		}
+	} else {
+		v.ContentType = "image/png"
+		if err := png.Encode(&buf, img); err != nil {
+			return Variant{}, err
+		}
+	}
+	v.Data = buf.Bytes()
+	return v, nil
+}
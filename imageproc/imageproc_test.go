@@ -0,0 +1,128 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// gradient draws a w×h image whose colour changes across it, so scaling and
+// hashing have something to work with.
+func gradient(w, h int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 128, A: alpha})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		original    [2]int
+		medium      [2]int
+		thumbnail   [2]int
+	}{
+		{
+			name:        "large photo",
+			data:        encodeJPEG(t, gradient(2048, 1024, 255)),
+			contentType: "image/jpeg",
+			original:    [2]int{2048, 1024},
+			medium:      [2]int{1024, 512},
+			thumbnail:   [2]int{320, 160},
+		},
+		{
+			name:        "portrait",
+			data:        encodePNG(t, gradient(400, 800, 255)),
+			contentType: "image/jpeg",
+			original:    [2]int{400, 800},
+			medium:      [2]int{400, 800},
+			thumbnail:   [2]int{160, 320},
+		},
+		{
+			name:        "transparent",
+			data:        encodePNG(t, gradient(100, 50, 100)),
+			contentType: "image/png",
+			original:    [2]int{100, 50},
+			medium:      [2]int{100, 50},
+			thumbnail:   [2]int{100, 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Process(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range []struct {
+				name    string
+				variant Variant
+				size    [2]int
+			}{
+				{"original", result.Original, tt.original},
+				{"medium", result.Medium, tt.medium},
+				{"thumbnail", result.Thumbnail, tt.thumbnail},
+			} {
+				if v.variant.ContentType != tt.contentType {
+					t.Errorf("%s content type = %s, want %s", v.name, v.variant.ContentType, tt.contentType)
+				}
+				if got := [2]int{v.variant.Width, v.variant.Height}; got != v.size {
+					t.Errorf("%s size = %v, want %v", v.name, got, v.size)
+				}
+				config, _, err := image.DecodeConfig(bytes.NewReader(v.variant.Data))
+				if err != nil {
+					t.Fatalf("%s does not decode: %v", v.name, err)
+				}
+				if config.Width != v.size[0] || config.Height != v.size[1] {
+					t.Errorf("%s encoded as %d×%d, want %v", v.name, config.Width, config.Height, v.size)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	header := encodePNG(t, gradient(20, 20, 255))
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text file", []byte("this is not an image, just some text"), ErrUnsupportedType},
+		{"too small", encodePNG(t, gradient(8, 8, 255)), ErrDimensions},
+		{"too wide", encodePNG(t, image.NewGray(image.Rect(0, 0, MaxDimension+1, 16))), ErrDimensions},
+		{"truncated", header[:len(header)/2], ErrCorrupt},
+		{"too large", append(encodePNG(t, gradient(16, 16, 255)), make([]byte, MaxBytes)...), ErrTooLarge},
+	}
+	for _, tt := range tests {
+		if _, err := Process(bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Process() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package imageproc
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+// Hash is a 64-bit perceptual hash. Photos of the same thing hash to values
+// a few bits apart even after scaling, recompression or small edits, so the
+// Hamming distance between two hashes measures how alike the images look.
+type Hash uint64
+
+// String returns the hash as 16 hex digits, the form it is stored in.
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// ParseHash reads a hash written by String.
+func ParseHash(s string) (Hash, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil || len(s) != 16 {
+		return 0, fmt.Errorf("invalid image hash %q", s)
+	}
+	return Hash(v), nil
+}
+
+// Distance is the number of bits in which h and other differ, from 0 for
+// near-identical images to 64.
+func (h Hash) Distance(other Hash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+const (
+	// hashSample is the side of the grayscale square the DCT runs on.
+	hashSample = 32
+	// hashSize is the side of the block of low frequencies kept.
+	hashSize = 8
+)
+
+// dctCos[u][x] is cos((2x+1)uπ / 2N), shared by every hash.
+var dctCos = func() [hashSize][hashSample]float64 {
+	var c [hashSize][hashSample]float64
+	for u := range hashSize {
+		for x := range hashSample {
+			c[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * hashSample))
+		}
+	}
+	return c
+}()
+
+// PerceptualHash computes the pHash of img: the image is shrunk to a 32×32
+// grayscale square, transformed with a DCT, and each of the 8×8 lowest
+// frequencies contributes one bit, set when it is above their median.
+func PerceptualHash(img image.Image) Hash {
+	gray := image.NewGray(image.Rect(0, 0, hashSample, hashSample))
+	draw.ApproxBiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var pixels [hashSample][hashSample]float64
+	for y := range hashSample {
+		for x := range hashSample {
+			pixels[y][x] = float64(gray.Pix[y*gray.Stride+x])
+		}
+	}
+
+	// Only the low frequencies are needed, so transform the rows and then
+	// the columns for those alone
+	var rows [hashSample][hashSize]float64
+	for y := range hashSample {
+		for u := range hashSize {
+			var sum float64
+			for x := range hashSample {
+				sum += pixels[y][x] * dctCos[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+	var coeffs [hashSize * hashSize]float64
+	for v := range hashSize {
+		for u := range hashSize {
+			var sum float64
+			for y := range hashSample {
+				sum += rows[y][u] * dctCos[v][y]
+			}
+			coeffs[v*hashSize+u] = sum
+		}
+	}
+
+	// The DC term is the average brightness and would skew the median
+	sorted := slices.Clone(coeffs[1:])
+	slices.Sort(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var h Hash
+	for i, coeff := range coeffs {
+		if coeff > median {
+			h |= 1 << i
+		}
+	}
+	return h
+}
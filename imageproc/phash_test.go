@@ -0,0 +1,92 @@
+package imageproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestHashString(t *testing.T) {
+	for _, h := range []Hash{0, 1, 0xdeadbeef, ^Hash(0)} {
+		s := h.String()
+		if len(s) != 16 {
+			t.Errorf("%d.String() = %q, want 16 digits", uint64(h), s)
+		}
+		got, err := ParseHash(s)
+		if err != nil || got != h {
+			t.Errorf("ParseHash(%q) = %v, %v, want %v", s, got, err, h)
+		}
+	}
+	for _, s := range []string{"", "abc", "zzzzzzzzzzzzzzzz", "00000000000000000"} {
+		if _, err := ParseHash(s); err == nil {
+			t.Errorf("ParseHash(%q) succeeded", s)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b Hash
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xff, 0x0f, 4},
+		{0, ^Hash(0), 64},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Distance(tt.b); got != tt.want {
+			t.Errorf("%v.Distance(%v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// checkerboard draws a pattern unlike the gradient used elsewhere.
+func checkerboard(w, h, square int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x/square+y/square)%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img
+}
+
+// scene draws a few blocks of different brightness, which gives the hash
+// more structure than a smooth gradient.
+func scene(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(40)
+			switch {
+			case x < w/3 && y < h/2:
+				v = 220
+			case x > w/2 && y > h/3:
+				v = 150
+			case y > 3*h/4:
+				v = 90
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHash(t *testing.T) {
+	photo := scene(640, 480)
+	base := PerceptualHash(photo)
+
+	if got := PerceptualHash(scene(640, 480)); got != base {
+		t.Errorf("same image hashed to %v and %v", base, got)
+	}
+	// Scaling keeps the hash within a few bits
+	if d := base.Distance(PerceptualHash(fit(photo, ThumbnailSize))); d > 4 {
+		t.Errorf("scaled copy is %d bits away", d)
+	}
+	if d := base.Distance(PerceptualHash(checkerboard(640, 480, 80))); d < 16 {
+		t.Errorf("different image is only %d bits away", d)
+	}
+}
@@ -0,0 +1,121 @@
+package imagestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+// Cloudinary stores images in a Cloudinary account. The key minus its
+// extension is the public ID; the extension picks the delivery format.
+type Cloudinary struct {
+	cld *cloudinary.Cloudinary
+}
+
+func NewCloudinary(cloudName, apiKey, apiSecret string) (*Cloudinary, error) {
+	cld, err := cloudinary.NewFromParams(cloudName, apiKey, apiSecret)
+	if err != nil {
+		return nil, err
+	}
+	cld.Config.URL.Secure = true
+	return &Cloudinary{cld: cld}, nil
+}
+
+func publicID(key string) string {
+	return strings.TrimSuffix(key, path.Ext(key))
+}
+
+func (s *Cloudinary) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+	overwrite := true
+	result, err := s.cld.Upload.Upload(ctx, r, uploader.UploadParams{
+		PublicID:  publicID(key),
+		Overwrite: &overwrite,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload image: %v", err)
+	}
+	if result.Error.Message != "" {
+		return fmt.Errorf("failed to upload image: %s", result.Error.Message)
+	}
+	return nil
+}
+
+func (s *Cloudinary) Delete(ctx context.Context, key string) error {
+	result, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicID(key)})
+	if err != nil {
+		return fmt.Errorf("failed to delete image: %v", err)
+	}
+	if result.Error.Message != "" {
+		return errors.New("failed to delete image: " + result.Error.Message)
+	}
+	// "not found" means there is nothing left to delete
+	if result.Result != "ok" && result.Result != "not found" {
+		return fmt.Errorf("failed to delete image: %s", result.Result)
+	}
+	return nil
+}
+
+func (s *Cloudinary) URL(key string) string {
+	image, err := s.cld.Image(key)
+	if err != nil {
+		return ""
+	}
+	url, err := image.String()
+	if err != nil {
+		return ""
+	}
+	return url
+}
+
+// versionSegment matches the "v1712345678" part of a delivery URL.
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+func (s *Cloudinary) Key(url string) (string, bool) {
+	_, rest, ok := strings.Cut(url, "/"+s.cld.Config.Cloud.CloudName+"/image/upload/")
+	if !ok {
+		return "", false
+	}
+	if version, after, ok := strings.Cut(rest, "/"); ok && versionSegment.MatchString(version) {
+		rest = after
+	}
+	return rest, validKey(rest)
+}
+
+func (s *Cloudinary) List(ctx context.Context, prefix string, fn func(Object) error) error {
+	params := admin.AssetsParams{
+		AssetType:    api.Image,
+		DeliveryType: "upload",
+		Prefix:       prefix,
+		MaxResults:   500,
+	}
+	for {
+		result, err := s.cld.Admin.Assets(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to list images: %v", err)
+		}
+		if result.Error.Message != "" {
+			return errors.New("failed to list images: " + result.Error.Message)
+		}
+		for _, asset := range result.Assets {
+			if err := fn(Object{Key: asset.PublicID + "." + asset.Format, Modified: asset.CreatedAt}); err != nil {
+				return err
+			}
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+		params.NextCursor = result.NextCursor
+	}
+}
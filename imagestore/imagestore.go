@@ -0,0 +1,159 @@
+// Package imagestore keeps the images attached to lost and found reports. The
+// backend is chosen at startup by Init, so the API can run against Cloudinary,
+// an S3-compatible bucket or a local directory.
+package imagestore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Store saves images under keys such as "lostfound/<id>.jpg" and knows the
+// public URL of each.
+type Store interface {
+	// Put saves the image read from r under key, replacing any image that
+	// was stored there before.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Delete removes the image stored under key. Deleting a key that does
+	// not exist is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients load the image from.
+	URL(key string) string
+	// Key returns the key of the image at url, the reverse of URL. It
+	// reports false for URLs that do not point into the store.
+	Key(url string) (string, bool)
+	// List calls fn for every image whose key starts with prefix.
+	List(ctx context.Context, prefix string, fn func(Object) error) error
+}
+
+// Object is a stored image as seen by List.
+type Object struct {
+	Key      string
+	Modified time.Time
+}
+
+// Default is the store used by Put, Delete and URL. It is nil until Init runs.
+var Default Store
+
+// Put saves an image with Default.
+func Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	return Default.Put(ctx, key, r, contentType)
+}
+
+// Delete removes an image from Default.
+func Delete(ctx context.Context, key string) error {
+	return Default.Delete(ctx, key)
+}
+
+// URL returns the address of an image in Default.
+func URL(key string) string {
+	return Default.URL(key)
+}
+
+// Key returns the key of an image in Default from its URL.
+func Key(url string) (string, bool) {
+	return Default.Key(url)
+}
+
+// List lists the images in Default.
+func List(ctx context.Context, prefix string, fn func(Object) error) error {
+	return Default.List(ctx, prefix, fn)
+}
+
+// Folder is the prefix of every key NewKey returns.
+const Folder = "lostfound"
+
+// NewKey returns a fresh key for an image with the given file extension,
+// e.g. ".jpg".
+func NewKey(ext string) string {
+	return Folder + "/" + primitive.NewObjectID().Hex() + strings.ToLower(ext)
+}
+
+// VariantKey returns the key of a variant of the image stored under key, e.g.
+// "lostfound/<id>_thumb.jpg" for variant "thumb".
+func VariantKey(key, variant string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + variant + ext
+}
+
+var errInvalidKey = errors.New("imagestore: invalid key")
+
+// validKey rejects keys that would escape the store's root, like "../x".
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	return path.Clean(key) == key && !strings.HasPrefix(key, "../") && key != ".."
+}
+
+// Init sets Default from the environment. IMAGE_STORE selects the backend:
+//
+//	cloudinary  CLOUDINARY_CLOUD_NAME, CLOUDINARY_API_KEY, CLOUDINARY_API_SECRET
+//	s3          S3_BUCKET, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY, S3_REGION (default
+//	            us-east-1), S3_ENDPOINT for MinIO and other compatible servers,
+//	            S3_PUBLIC_URL if clients reach the bucket through another address
+//	local       files in IMAGE_DIR (default "uploads"), served by the API itself
+//	            under LocalRoute; IMAGE_BASE_URL is the public address of that route
+//
+// Without IMAGE_STORE, Cloudinary is used when it is configured and the
+// local directory otherwise.
+func Init() {
+	backend := os.Getenv("IMAGE_STORE")
+	if backend == "" {
+		backend = "local"
+		if os.Getenv("CLOUDINARY_CLOUD_NAME") != "" {
+			backend = "cloudinary"
+		}
+	}
+
+	switch backend {
+	case "cloudinary":
+		s, err := NewCloudinary(
+			os.Getenv("CLOUDINARY_CLOUD_NAME"),
+			os.Getenv("CLOUDINARY_API_KEY"),
+			os.Getenv("CLOUDINARY_API_SECRET"),
+		)
+		if err != nil {
+			log.Fatalf("Failed to initialize Cloudinary: %v", err)
+		}
+		Default = s
+	case "s3":
+		s, err := NewS3(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize S3 image store: %v", err)
+		}
+		Default = s
+	case "local":
+		dir := os.Getenv("IMAGE_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		baseURL := os.Getenv("IMAGE_BASE_URL")
+		if baseURL == "" {
+			port := os.Getenv("PORT")
+			if port == "" {
+				port = "5000"
+			}
+			baseURL = "http://localhost:" + port + LocalRoute
+		}
+		Default = &Local{Dir: dir, BaseURL: baseURL}
+		log.Printf("Storing images in %s, served from %s", dir, baseURL)
+	default:
+		log.Fatalf("Unknown IMAGE_STORE %q", backend)
+	}
+}
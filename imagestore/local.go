@@ -0,0 +1,104 @@
+package imagestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalRoute is the path the API serves a Local store's directory under.
+const LocalRoute = "/uploads"
+
+// Local stores images as files under Dir. The API serves Dir itself, so
+// URLs are BaseURL followed by the key.
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+func (s *Local) path(key string) string {
+	return filepath.Join(s.Dir, filepath.FromSlash(key))
+}
+
+func (s *Local) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+	dest := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("imagestore: %w", err)
+	}
+
+	// Write to a temporary file first so a failed upload never leaves a
+	// truncated image behind
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("imagestore: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("imagestore: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("imagestore: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("imagestore: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("imagestore: %w", err)
+	}
+	return nil
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("imagestore: %w", err)
+	}
+	return nil
+}
+
+func (s *Local) URL(key string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + key
+}
+
+func (s *Local) Key(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, strings.TrimSuffix(s.BaseURL, "/")+"/")
+	return key, ok && validKey(key)
+}
+
+func (s *Local) List(ctx context.Context, prefix string, fn func(Object) error) error {
+	// Nothing was uploaded yet
+	if _, err := os.Stat(s.Dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(s.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return ctx.Err()
+		}
+		rel, err := filepath.Rel(s.Dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return fn(Object{Key: key, Modified: info.ModTime()})
+	})
+}
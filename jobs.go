@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"lostfound-backend/imagestore"
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+	"lostfound-backend/utils"
+)
+
+// runLostItemExpiry marks open lost items as expired once they are older than
+// LOST_ITEM_TTL_DAYS (default 90), checking every hour.
+func runLostItemExpiry() {
+	ttlDays := 90
+	if v := os.Getenv("LOST_ITEM_TTL_DAYS"); v != "" {
+		days, err := utils.StringToInt(v)
+		if err != nil || days <= 0 {
+			log.Printf("⚠️ Invalid LOST_ITEM_TTL_DAYS %q, using %d", v, ttlDays)
+		} else {
+			ttlDays = days
+		}
+	}
+	ttl := time.Duration(ttlDays) * 24 * time.Hour
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		expired, err := store.LostItems.ExpireStale(context.Background(), time.Now().Add(-ttl))
+		if err != nil {
+			log.Println("ExpireStale error:", err)
+		} else if expired > 0 {
+			log.Printf("Expired %d lost items", expired)
+		}
+		<-ticker.C
+	}
+}
+
+// imageSweepGrace is how old a stored image must be before the sweeper may
+// delete it, so uploads whose report is still being saved are left alone.
+const imageSweepGrace = 24 * time.Hour
+
+// runImageSweeper deletes stored images no lost or found report refers to,
+// e.g. left behind by a crash between upload and save, every
+// IMAGE_SWEEP_INTERVAL_HOURS (default 24). An interval of 0 turns it off.
+func runImageSweeper() {
+	intervalHours := 24
+	if v := os.Getenv("IMAGE_SWEEP_INTERVAL_HOURS"); v != "" {
+		hours, err := utils.StringToInt(v)
+		if err != nil || hours < 0 {
+			log.Printf("⚠️ Invalid IMAGE_SWEEP_INTERVAL_HOURS %q, using %d", v, intervalHours)
+		} else {
+			intervalHours = hours
+		}
+	}
+	if intervalHours == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(intervalHours) * time.Hour)
+	defer ticker.Stop()
+	for {
+		deleted, err := sweepImages(context.Background(), time.Now().Add(-imageSweepGrace))
+		if err != nil {
+			log.Println("Image sweep error:", err)
+		} else if deleted > 0 {
+			log.Printf("Deleted %d orphaned images", deleted)
+		}
+		<-ticker.C
+	}
+}
+
+// sweepImages deletes the images stored before cutoff that no report refers
+// to and returns how many it deleted.
+func sweepImages(ctx context.Context, cutoff time.Time) (int, error) {
+	// Load the references before listing, so an image saved in between is
+	// at worst kept until the next sweep
+	referenced := map[string]bool{}
+	for _, load := range []func(context.Context) ([]models.Image, error){store.LostItems.Images, store.FoundItems.Images} {
+		images, err := load(ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, image := range images {
+			key := image.Key
+			if key == "" {
+				var ok bool
+				if key, ok = imagestore.Key(image.URL); !ok {
+					continue
+				}
+			}
+			referenced[key] = true
+			referenced[imagestore.VariantKey(key, "medium")] = true
+			referenced[imagestore.VariantKey(key, "thumb")] = true
+		}
+	}
+
+	var orphans []string
+	err := imagestore.List(ctx, imagestore.Folder+"/", func(object imagestore.Object) error {
+		if !referenced[object.Key] && object.Modified.Before(cutoff) {
+			orphans = append(orphans, object.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, key := range orphans {
+		if err := imagestore.Delete(ctx, key); err != nil {
+			log.Println("Delete image error:", err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"lostfound-backend/db"
+	"lostfound-backend/imagestore"
+	"lostfound-backend/mailer"
+	"lostfound-backend/models"
 	"lostfound-backend/routes"
-	"lostfound-backend/utils"
+	"lostfound-backend/store"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -21,18 +26,57 @@ func main() {
 		log.Println("Warning: .env file not found, using system environment variables")
 	}
 
-	// Get Mongo URI from env or fallback
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		log.Fatal("❌ MONGODB_URI not set in environment variables")
+	// "migrate" runs the database migrations instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrateCommand(os.Args[2:])
+		return
 	}
 
-	// Connect to MongoDB
-	db.ConnectMongoDB(mongoURI)
-	defer db.DisconnectMongoDB()
+	// STORE_BACKEND=memory runs the API without a database, e.g. for tests
+	if os.Getenv("STORE_BACKEND") == "memory" {
+		log.Println("⚠️ Using in-memory store, data will not be persisted")
+		store.UseMemory()
+	} else {
+		// Get Mongo URI from env or fallback
+		mongoURI := os.Getenv("MONGODB_URI")
+		if mongoURI == "" {
+			log.Fatal("❌ MONGODB_URI not set in environment variables")
+		}
+
+		// Connect to MongoDB
+		db.ConnectMongoDB(mongoURI)
+		defer db.DisconnectMongoDB()
+		store.UseMongo()
+
+		// MIGRATE_ON_STARTUP=false leaves migrations to "migrate", e.g. when
+		// several instances start at once
+		if os.Getenv("MIGRATE_ON_STARTUP") != "false" {
+			if err := runMigrations(context.Background()); err != nil {
+				log.Fatal("❌ Failed to migrate the database: ", err)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := store.EnsureIndexes(ctx); err != nil {
+			log.Fatal("❌ Failed to create indexes: ", err)
+		}
+		cancel()
+	}
+
+	// Pick where uploaded images are stored
+	imagestore.Init()
+
+	// Pick the mail transport for verification and password reset links
+	mailer.Init()
 
-	// Initialize Cloudinary
-	utils.InitCloudinary()
+	// Make sure the configured admins have the role
+	promoteAdmins()
+
+	// Expire lost items nobody has touched in a long time
+	go runLostItemExpiry()
+
+	// Delete uploaded images no report refers to any more
+	go runImageSweeper()
 
 	// Create Gin router
 	router := gin.Default()
@@ -40,11 +84,16 @@ func main() {
 	// Use CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Allow all origins
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Last-Event-ID"},
 		AllowCredentials: true,
 	}))
 
+	// Serve uploaded images when they are stored on local disk
+	if local, ok := imagestore.Default.(*imagestore.Local); ok {
+		router.Static(imagestore.LocalRoute, local.Dir)
+	}
+
 	// API Routes
 	api := router.Group("/api")
 
@@ -54,8 +103,21 @@ func main() {
 		auth.POST("/signup", routes.Signup)
 		auth.POST("/login", routes.Login)
 		auth.GET("/check-session", routes.CheckSession)
+		auth.POST("/refresh", routes.RefreshToken)
+		auth.POST("/logout", routes.AuthMiddleware(), routes.Logout)
+		auth.POST("/forgot-password", routes.ForgotPassword)
+		auth.POST("/reset-password", routes.ResetPassword)
+		auth.POST("/verify-email", routes.VerifyEmail)
+		auth.POST("/resend-verification", routes.AuthMiddleware(), routes.ResendVerification)
 	}
 
+	// Notification stream (EventSource cannot send an Authorization header)
+	api.GET("/notifications/stream", routes.TokenFromQuery(), routes.AuthMiddleware(), routes.StreamNotifications)
+
+	// REQUIRE_VERIFIED_EMAIL=true only lets users with a verified email post
+	// lost and found reports
+	verified := routes.RequireVerifiedEmail(os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true")
+
 	// Protected routes (require auth)
 	protected := api.Group("")
 	protected.Use(routes.AuthMiddleware())
@@ -65,22 +127,66 @@ func main() {
 		protected.PUT("/auth/user/:id", routes.UpdateUser)
 
 		// Lost items routes
-		protected.POST("/lostitems", routes.AddLostItem)
+		protected.POST("/lostitems", verified, routes.AddLostItem)
 		protected.GET("/lostitems", routes.GetAllLostItems)
 		protected.GET("/lostitems/user/:userId", routes.GetLostItemsByUser)
 		protected.GET("/lostitems/filters", routes.GetFilterOptions)
 		protected.GET("/lostitems/:id", routes.GetLostItemByID)
+		protected.GET("/lostitems/:id/matches", routes.GetLostItemMatches)
+		protected.GET("/lostitems/:id/similar-found", routes.GetSimilarFoundItems)
+		protected.PATCH("/lostitems/:id", routes.UpdateLostItem)
+		protected.PUT("/lostitems/:id/status", routes.UpdateLostItemStatus)
 		protected.DELETE("/lostitems/:id", routes.DeleteLostItem)
+		protected.POST("/lostitems/:id/images", routes.AddLostItemImages)
+		protected.PUT("/lostitems/:id/images/order", routes.ReorderLostItemImages)
+		protected.PATCH("/lostitems/:id/images/:imageId", routes.UpdateLostItemImage)
+		protected.DELETE("/lostitems/:id/images/:imageId", routes.RemoveLostItemImage)
 
 		// Found items routes
-		protected.POST("/founditems", routes.AddFoundItem)
+		protected.POST("/founditems", verified, routes.AddFoundItem)
 		protected.GET("/founditems", routes.GetAllFoundItems)
 		protected.GET("/founditems/lostItem/:lostItemId", routes.GetFoundItemsByLostItem)
 		protected.GET("/founditems/foundPerson/:foundPersonId", routes.GetFoundItemsByUser)
 		protected.GET("/founditems/:id", routes.GetFoundItemByID)
+		protected.GET("/founditems/:id/matches", routes.GetFoundItemMatches)
 		protected.PUT("/founditems/:id", routes.UpdateFoundItem)
 		protected.PUT("/founditems/:id/found", routes.UpdateFoundStatus)
 		protected.DELETE("/founditems/:id", routes.DeleteFoundItem)
+		protected.POST("/founditems/:id/images", routes.AddFoundItemImages)
+		protected.PUT("/founditems/:id/images/order", routes.ReorderFoundItemImages)
+		protected.PATCH("/founditems/:id/images/:imageId", routes.UpdateFoundItemImage)
+		protected.DELETE("/founditems/:id/images/:imageId", routes.RemoveFoundItemImage)
+
+		// Claim routes
+		protected.POST("/founditems/:id/claims", routes.CreateClaim)
+		protected.GET("/founditems/:id/claims", routes.GetFoundItemClaims)
+		protected.GET("/claims", routes.GetMyClaims)
+		protected.GET("/claims/:id", routes.GetClaimByID)
+		protected.POST("/claims/:id/questions", routes.AddClaimQuestion)
+		protected.PUT("/claims/:id/questions/:questionId", routes.AnswerClaimQuestion)
+		protected.PUT("/claims/:id/status", routes.UpdateClaimStatus)
+
+		// Notification routes
+		protected.GET("/notifications", routes.ListNotifications)
+		protected.GET("/notifications/unread-count", routes.GetUnreadNotificationCount)
+		protected.PUT("/notifications/read-all", routes.MarkAllNotificationsRead)
+		protected.PUT("/notifications/:id/read", routes.MarkNotificationRead)
+		protected.DELETE("/notifications/:id", routes.DeleteNotification)
+
+		// Conversation routes
+		protected.POST("/conversations", routes.CreateConversation)
+		protected.GET("/conversations", routes.ListConversations)
+		protected.GET("/conversations/unread-count", routes.GetUnreadMessageCount)
+		protected.GET("/conversations/:id", routes.GetConversation)
+		protected.GET("/conversations/:id/messages", routes.ListMessages)
+		protected.POST("/conversations/:id/messages", routes.SendMessage)
+		protected.PUT("/conversations/:id/read", routes.MarkConversationRead)
+
+		// Report routes
+		protected.POST("/reports", routes.CreateReport)
+
+		// Search routes
+		protected.GET("/search", routes.Search)
 
 		// Bookmark routes
 		protected.POST("/bookmarks", routes.AddBookmark)
@@ -88,6 +194,26 @@ func main() {
 		protected.DELETE("/bookmarks/:id", routes.DeleteBookmark)
 	}
 
+	// Moderation routes (moderators and admins)
+	admin := api.Group("/admin")
+	admin.Use(routes.AuthMiddleware(), routes.RequireRole(models.RoleModerator, models.RoleAdmin))
+	{
+		admin.GET("/lostitems", routes.AdminListLostItems)
+		admin.PUT("/lostitems/:id/hidden", routes.AdminSetLostItemHidden)
+		admin.DELETE("/lostitems/:id", routes.AdminDeleteLostItem)
+		admin.GET("/founditems", routes.AdminListFoundItems)
+		admin.PUT("/founditems/:id/hidden", routes.AdminSetFoundItemHidden)
+		admin.DELETE("/founditems/:id", routes.AdminDeleteFoundItem)
+		admin.PUT("/messages/:id/hidden", routes.AdminSetMessageHidden)
+		admin.GET("/reports", routes.AdminListReports)
+		admin.PUT("/reports/:id/resolve", routes.ResolveReport)
+		admin.PUT("/reports/:id/dismiss", routes.DismissReport)
+		admin.GET("/audit", routes.GetAuditLog)
+		admin.PUT("/users/:id/suspension", routes.SuspendUser)
+		admin.DELETE("/users/:id/suspension", routes.UnsuspendUser)
+		admin.PUT("/users/:id/role", routes.RequireRole(models.RoleAdmin), routes.SetUserRole)
+	}
+
 	// Get port or fallback to 5000
 	port := os.Getenv("PORT")
 	if port == "" {
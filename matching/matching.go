@@ -0,0 +1,75 @@
+package matching
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+)
+
+// MatchFoundItem scores found against the open lost items in its date window,
+// replacing any matches previously stored for it. The stored matches are
+// returned with LostItemDetails populated.
+func MatchFoundItem(ctx context.Context, found *models.FoundItem) ([]models.Match, error) {
+	lostItems, err := store.LostItems.List(ctx, store.LostItemFilter{
+		Statuses:   []models.LostItemStatus{models.LostItemOpen, models.LostItemMatched},
+		LostAfter:  found.DateFound.Add(-DateWindow),
+		LostBefore: found.DateFound.Add(DateSlack),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Matches.DeleteByFoundItem(ctx, found.ID); err != nil {
+		return nil, err
+	}
+
+	var matches []models.Match
+	for i := range lostItems {
+		lost := &lostItems[i]
+		match := Score(lost, found)
+		if match.Score < MinScore {
+			continue
+		}
+		if err := store.Matches.Upsert(ctx, &match); err != nil {
+			return matches, err
+		}
+		match.LostItemDetails = lost
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// MatchLostItem scores lost against the unreturned found items in its date
+// window, replacing any matches previously stored for it. The stored matches
+// are returned with FoundItemDetails populated.
+func MatchLostItem(ctx context.Context, lost *models.LostItem) ([]models.Match, error) {
+	notReturned := false
+	foundItems, err := store.FoundItems.List(ctx, store.FoundItemFilter{
+		Found:       &notReturned,
+		FoundAfter:  lost.DateLost.Add(-DateSlack),
+		FoundBefore: lost.DateLost.Add(DateWindow),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Matches.DeleteByLostItem(ctx, lost.ID); err != nil {
+		return nil, err
+	}
+
+	var matches []models.Match
+	for i := range foundItems {
+		found := &foundItems[i]
+		match := Score(lost, found)
+		if match.Score < MinScore {
+			continue
+		}
+		if err := store.Matches.Upsert(ctx, &match); err != nil {
+			return matches, err
+		}
+		match.FoundItemDetails = found
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
@@ -0,0 +1,186 @@
+// Package matching scores found items against lost items and records the
+// likely pairs as models.Match documents.
+package matching
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"lostfound-backend/models"
+)
+
+const (
+	// MinScore is the lowest score worth storing as a candidate match.
+	MinScore = 0.35
+
+	// DateSlack allows a found report to predate the lost report, since
+	// owners often report a loss a day or so after it happened.
+	DateSlack = 48 * time.Hour
+
+	// DateWindow is how long after a loss a found report is still considered.
+	DateWindow = 60 * 24 * time.Hour
+
+	// NearbyRadius is the distance in meters at which coordinates stop
+	// counting towards the location score.
+	NearbyRadius = 5000.0
+)
+
+// Signal weights; they sum to 1 so Score stays in [0, 1].
+const (
+	categoryWeight = 0.25
+	locationWeight = 0.25
+	dateWeight     = 0.15
+	textWeight     = 0.35
+)
+
+// Score compares a lost item with a found item. The returned match has its
+// item IDs, score and breakdown set but is not persisted.
+func Score(lost *models.LostItem, found *models.FoundItem) models.Match {
+	b := models.MatchBreakdown{
+		Category: categoryScore(lost, found),
+		Location: locationScore(lost, found),
+		Date:     dateScore(lost.DateLost, found.DateFound),
+		Text:     textScore(lost, found),
+	}
+	score := categoryWeight*b.Category + locationWeight*b.Location + dateWeight*b.Date + textWeight*b.Text
+
+	// A found report that predates the loss by more than the slack cannot be it.
+	if b.Date == 0 && found.DateFound.Before(lost.DateLost) {
+		score = 0
+	}
+
+	return models.Match{
+		LostItem:  lost.ID,
+		FoundItem: found.ID,
+		Score:     round(score),
+		Breakdown: b,
+	}
+}
+
+func categoryScore(lost *models.LostItem, found *models.FoundItem) float64 {
+	if lost.Category == "" {
+		return 0
+	}
+	if found.Category != "" {
+		if strings.EqualFold(strings.TrimSpace(lost.Category), strings.TrimSpace(found.Category)) {
+			return 1
+		}
+		return 0
+	}
+	// Found reports without a category: look for it in the finder's wording.
+	words := tokens(found.Name + " " + found.Description)
+	for w := range tokens(lost.Category) {
+		if words[w] {
+			return 0.5
+		}
+	}
+	return 0
+}
+
+func locationScore(lost *models.LostItem, found *models.FoundItem) float64 {
+	best := 0.0
+	switch {
+	case found.District != "" && strings.EqualFold(found.District, lost.District):
+		best = 1
+	case found.State != "" && strings.EqualFold(found.State, lost.State):
+		best = 0.5
+	}
+
+	// Exact coordinates beat any textual comparison when both sides have them
+	if lost.Position != nil && found.Position != nil {
+		d := lost.Position.DistanceTo(found.Position)
+		if d < NearbyRadius {
+			best = max(best, 1-d/NearbyRadius)
+		}
+	}
+
+	place := tokens(found.LocationFound)
+	if len(place) == 0 {
+		return best
+	}
+	if lost.District != "" && containsAll(place, tokens(lost.District)) {
+		best = max(best, 0.8)
+	}
+	for _, loc := range lost.Locations {
+		best = max(best, jaccard(place, tokens(loc)))
+	}
+	return round(best)
+}
+
+// dateScore is 1 when the item was found up to DateSlack before or at the
+// time of the loss, decaying linearly to 0 at DateWindow afterwards.
+func dateScore(lostAt, foundAt time.Time) float64 {
+	if lostAt.IsZero() || foundAt.IsZero() {
+		return 0
+	}
+	delta := foundAt.Sub(lostAt)
+	switch {
+	case delta < -DateSlack:
+		return 0
+	case delta <= 0:
+		return 1
+	case delta >= DateWindow:
+		return 0
+	}
+	return round(1 - float64(delta)/float64(DateWindow))
+}
+
+// textScore weighs name overlap above overlap of the full descriptions.
+func textScore(lost *models.LostItem, found *models.FoundItem) float64 {
+	names := jaccard(tokens(lost.Name), tokens(found.Name))
+	all := jaccard(
+		tokens(lost.Name+" "+lost.Description),
+		tokens(found.Name+" "+found.Description),
+	)
+	return round(0.6*names + 0.4*all)
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true,
+	"in": true, "is": true, "it": true, "my": true, "near": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "was": true, "with": true,
+}
+
+// tokens lowercases s and splits it into its distinct words, dropping stop
+// words and single characters.
+func tokens(s string) map[string]bool {
+	out := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) > 1 && !stopWords[w] {
+			out[w] = true
+		}
+	}
+	return out
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for w := range a {
+		if b[w] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func containsAll(set, words map[string]bool) bool {
+	if len(words) == 0 {
+		return false
+	}
+	for w := range words {
+		if !set[w] {
+			return false
+		}
+	}
+	return true
+}
+
+func round(f float64) float64 {
+	return float64(int(f*1000+0.5)) / 1000
+}
@@ -0,0 +1,135 @@
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"lostfound-backend/models"
+)
+
+var lostAt = time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func lostWallet() *models.LostItem {
+	return &models.LostItem{
+		Name:        "Black leather wallet",
+		Description: "Wallet with my ID and bank cards",
+		Category:    "Wallets",
+		District:    "Colombo",
+		State:       "Western",
+		Locations:   []string{"Fort railway station"},
+		DateLost:    lostAt,
+	}
+}
+
+func TestDateScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		found time.Time
+		want  float64
+	}{
+		{"same time", lostAt, 1},
+		{"day before", lostAt.Add(-24 * time.Hour), 1},
+		{"beyond slack", lostAt.Add(-DateSlack - time.Hour), 0},
+		{"half the window", lostAt.Add(DateWindow / 2), 0.5},
+		{"end of window", lostAt.Add(DateWindow), 0},
+		{"unknown", time.Time{}, 0},
+	}
+	for _, tt := range tests {
+		if got := dateScore(lostAt, tt.found); got != tt.want {
+			t.Errorf("%s: dateScore() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		found    models.FoundItem
+		want     float64
+	}{
+		{"same category", "Wallets", models.FoundItem{Category: " wallets "}, 1},
+		{"other category", "Wallets", models.FoundItem{Category: "Phones"}, 0},
+		{"named in description", "Wallets", models.FoundItem{Description: "brown wallets"}, 0.5},
+		{"no lost category", "", models.FoundItem{Category: "Wallets"}, 0},
+	}
+	for _, tt := range tests {
+		lost := &models.LostItem{Category: tt.category}
+		if got := categoryScore(lost, &tt.found); got != tt.want {
+			t.Errorf("%s: categoryScore() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLocationScore(t *testing.T) {
+	near := &models.GeoPoint{Type: "Point", Coordinates: []float64{79.85, 6.93}}
+	tests := []struct {
+		name  string
+		lost  func(*models.LostItem)
+		found models.FoundItem
+		want  float64
+	}{
+		{"same district", nil, models.FoundItem{District: "colombo"}, 1},
+		{"same state", nil, models.FoundItem{District: "Gampaha", State: "Western"}, 0.5},
+		{"district named in place", nil, models.FoundItem{LocationFound: "Near Colombo bus stand"}, 0.8},
+		{"listed location", nil, models.FoundItem{LocationFound: "Fort railway station"}, 1},
+		{"elsewhere", nil, models.FoundItem{District: "Kandy", State: "Central"}, 0},
+		{"same coordinates", func(l *models.LostItem) { l.District, l.State, l.Position = "", "", near }, models.FoundItem{Position: near}, 1},
+	}
+	for _, tt := range tests {
+		lost := lostWallet()
+		if tt.lost != nil {
+			tt.lost(lost)
+		}
+		if got := locationScore(lost, &tt.found); got != tt.want {
+			t.Errorf("%s: locationScore() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		found   models.FoundItem
+		atLeast float64
+		below   float64
+	}{
+		{
+			name: "same wallet",
+			found: models.FoundItem{
+				Name:        "Black leather wallet",
+				Description: "Found a wallet with bank cards",
+				Category:    "Wallets",
+				District:    "Colombo",
+				DateFound:   lostAt.Add(6 * time.Hour),
+			},
+			atLeast: 0.8, below: 1.001,
+		},
+		{
+			name: "unrelated item",
+			found: models.FoundItem{
+				Name:      "Umbrella",
+				Category:  "Umbrellas",
+				District:  "Kandy",
+				DateFound: lostAt.Add(DateWindow),
+			},
+			atLeast: 0, below: MinScore,
+		},
+		{
+			name: "found long before the loss",
+			found: models.FoundItem{
+				Name:      "Black leather wallet",
+				Category:  "Wallets",
+				District:  "Colombo",
+				DateFound: lostAt.Add(-DateSlack - 24*time.Hour),
+			},
+			atLeast: 0, below: 0.001,
+		},
+	}
+	for _, tt := range tests {
+		match := Score(lostWallet(), &tt.found)
+		if match.Score < tt.atLeast || match.Score >= tt.below {
+			t.Errorf("%s: Score() = %v (%+v), want within [%v, %v)", tt.name, match.Score, match.Breakdown, tt.atLeast, tt.below)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"lostfound-backend/db"
+	"lostfound-backend/store"
+)
+
+// migrateCommand implements "migrate", which applies the pending database
+// migrations, and "migrate status", which lists them without applying any.
+func migrateCommand(args []string) {
+	mongoURI := os.Getenv("MONGODB_URI")
+	if mongoURI == "" {
+		log.Fatal("❌ MONGODB_URI not set in environment variables")
+	}
+	db.ConnectMongoDB(mongoURI)
+	defer db.DisconnectMongoDB()
+	store.UseMongo()
+
+	ctx := context.Background()
+	switch {
+	case len(args) == 0:
+		if err := runMigrations(ctx); err != nil {
+			log.Fatal("❌ Failed to migrate the database: ", err)
+		}
+	case len(args) == 1 && args[0] == "status":
+		applied, err := store.AppliedMigrations(ctx)
+		if err != nil {
+			log.Fatal("❌ Failed to read migrations: ", err)
+		}
+		pending, err := store.PendingMigrations(ctx)
+		if err != nil {
+			log.Fatal("❌ Failed to read migrations: ", err)
+		}
+		for _, m := range applied {
+			state := "applied " + m.AppliedAt.Format("2006-01-02 15:04")
+			fmt.Printf("%4d  %-24s  %s (%d changed)\n", m.Version, state, m.Description, m.Changed)
+		}
+		for _, m := range pending {
+			fmt.Printf("%4d  %-24s  %s\n", m.Version, "pending", m.Description)
+		}
+	default:
+		log.Fatal("usage: migrate [status]")
+	}
+}
+
+// runMigrations applies the pending migrations, logging each one.
+func runMigrations(ctx context.Context) error {
+	applied, err := store.Migrate(ctx)
+	for _, m := range applied {
+		log.Printf("Applied migration %d: %s (%d changed)", m.Version, m.Description, m.Changed)
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ClaimStatus string
+
+const (
+	ClaimPending   ClaimStatus = "pending"
+	ClaimApproved  ClaimStatus = "approved"
+	ClaimRejected  ClaimStatus = "rejected"
+	ClaimWithdrawn ClaimStatus = "withdrawn"
+)
+
+// CanTransition reports whether a claim may move from s to next. Only pending
+// claims can change; every other state is final.
+func (s ClaimStatus) CanTransition(next ClaimStatus) bool {
+	if s != ClaimPending {
+		return false
+	}
+	switch next {
+	case ClaimApproved, ClaimRejected, ClaimWithdrawn:
+		return true
+	}
+	return false
+}
+
+// VerificationQuestion is asked privately by the finder to check that the
+// claimant really owns the item.
+type VerificationQuestion struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Question   string             `bson:"question" json:"question"`
+	Answer     string             `bson:"answer,omitempty" json:"answer,omitempty"`
+	AskedAt    time.Time          `bson:"askedAt" json:"askedAt"`
+	AnsweredAt time.Time          `bson:"answeredAt,omitempty" json:"answeredAt,omitempty"`
+}
+
+// Claim is an ownership claim on a FoundItem. Only the claimant and the
+// finder (the FoundItem's FoundPerson) may see it.
+type Claim struct {
+	ID           primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	FoundItem    primitive.ObjectID     `bson:"foundItem" json:"foundItem"`
+	LostItem     primitive.ObjectID     `bson:"lostItem,omitempty" json:"lostItem,omitempty"`
+	Claimant     primitive.ObjectID     `bson:"claimant" json:"claimant"`
+	Finder       primitive.ObjectID     `bson:"finder" json:"finder"`
+	Message      string                 `bson:"message" json:"message"`
+	Status       ClaimStatus            `bson:"status" json:"status"`
+	Questions    []VerificationQuestion `bson:"questions" json:"questions"`
+	DecisionNote string                 `bson:"decisionNote,omitempty" json:"decisionNote,omitempty"`
+	DecidedAt    time.Time              `bson:"decidedAt,omitempty" json:"decidedAt,omitempty"`
+	CreatedAt    time.Time              `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time              `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Unanswered returns how many verification questions still lack an answer.
+func (c *Claim) Unanswered() int {
+	n := 0
+	for _, q := range c.Questions {
+		if q.Answer == "" {
+			n++
+		}
+	}
+	return n
+}
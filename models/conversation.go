@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Conversation is a private message thread between the owner of a LostItem
+// and the finder of a FoundItem. There is at most one per item pair.
+type Conversation struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LostItem      primitive.ObjectID `bson:"lostItem" json:"lostItem"`
+	FoundItem     primitive.ObjectID `bson:"foundItem" json:"foundItem"`
+	Owner         primitive.ObjectID `bson:"owner" json:"owner"`   // CreatedBy of the lost item
+	Finder        primitive.ObjectID `bson:"finder" json:"finder"` // FoundPerson of the found item
+	OwnerReadAt   time.Time          `bson:"ownerReadAt,omitempty" json:"ownerReadAt,omitempty"`
+	FinderReadAt  time.Time          `bson:"finderReadAt,omitempty" json:"finderReadAt,omitempty"`
+	LastMessageAt time.Time          `bson:"lastMessageAt,omitempty" json:"lastMessageAt,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+
+	// Response only: messages from the other participant the caller has not read
+	UnreadCount int64 `bson:"-" json:"unreadCount"`
+}
+
+// HasParticipant reports whether userID is the owner or the finder.
+func (c *Conversation) HasParticipant(userID primitive.ObjectID) bool {
+	return userID == c.Owner || userID == c.Finder
+}
+
+// Other returns the participant who is not userID.
+func (c *Conversation) Other(userID primitive.ObjectID) primitive.ObjectID {
+	if userID == c.Owner {
+		return c.Finder
+	}
+	return c.Owner
+}
+
+// ReadAt returns when userID last read the conversation.
+func (c *Conversation) ReadAt(userID primitive.ObjectID) time.Time {
+	if userID == c.Owner {
+		return c.OwnerReadAt
+	}
+	return c.FinderReadAt
+}
+
+type Message struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Conversation primitive.ObjectID `bson:"conversation" json:"conversation"`
+	Sender       primitive.ObjectID `bson:"sender" json:"sender"`
+	Body         string             `bson:"body" json:"body"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	Hidden       *ModerationAction  `bson:"hidden,omitempty" json:"hidden,omitempty"` // Set while a moderator hides the message
+}
@@ -8,21 +8,38 @@ import (
 
 type FoundItem struct {
 	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	LostPerson       primitive.ObjectID `bson:"lostPerson,omitempty" json:"lostPerson"`
-	FoundPerson      primitive.ObjectID `bson:"foundPerson,omitempty" json:"foundPerson"` // Same as FoundBy in routes
-	FoundPersonPhone string             `bson:"foundPersonPhone" json:"foundPersonPhone"`
+	LostItem         primitive.ObjectID `bson:"lostItem,omitempty" json:"lostItem,omitempty"` // The lost report this item answers, if known
+	LostPerson       primitive.ObjectID `bson:"lostPerson,omitempty" json:"lostPerson"`       // Owner of LostItem
+	FoundPerson      primitive.ObjectID `bson:"foundPerson,omitempty" json:"foundPerson"`     // Same as FoundBy in routes
+	FoundPersonPhone string             `bson:"foundPersonPhone" json:"-"`                    // Shown only through ContactPhone
 	LocationFound    string             `bson:"locationFound" json:"locationFound"`
+	Position         *GeoPoint          `bson:"position,omitempty" json:"position,omitempty"` // Where the item was found
 	DateFound        time.Time          `bson:"dateFound" json:"dateFound"`
 	Name             string             `bson:"name" json:"name"`
 	Image            string             `bson:"image,omitempty" json:"image"` // Same as ImageURL in routes
+	ImageMedium      string             `bson:"imageMedium,omitempty" json:"imageMedium,omitempty"`
+	ImageThumbnail   string             `bson:"imageThumbnail,omitempty" json:"imageThumbnail,omitempty"`
+	Images           []Image            `bson:"images,omitempty" json:"images,omitempty" form:"-"` // Image and its variants mirror the primary one
+	ImagesVersion    int                `bson:"imagesVersion,omitempty" json:"-" form:"-"`         // Counts changes to Images, see LostItem
 	Description      string             `bson:"description" json:"description"`
+	Category         string             `bson:"category,omitempty" json:"category,omitempty"`
+	District         string             `bson:"district,omitempty" json:"district,omitempty"`
+	State            string             `bson:"state,omitempty" json:"state,omitempty"`
 	Found            bool               `bson:"found" json:"found"`
+	Hidden           *ModerationAction  `bson:"hidden,omitempty" json:"hidden,omitempty"` // Set while a moderator hides the report
 	CreatedAt        time.Time          `bson:"createdAt,omitempty" json:"createdAt"`
 	UpdatedAt        time.Time          `bson:"updatedAt,omitempty" json:"updatedAt"`
 
 	// Add these for response only (not stored in DB)
-	FoundByUser    *User `bson:"-" json:"foundByUser,omitempty"`
-	LostPersonUser *User `bson:"-" json:"lostPersonUser,omitempty"`
+	Distance        *float64  `bson:"-" json:"distance,omitempty"`      // Meters from a near= search
+	Score           *float64  `bson:"-" json:"score,omitempty"`         // Relevance from a q= search
+	ImageDistance   *int      `bson:"-" json:"imageDistance,omitempty"` // Hash distance from a similar-found search
+	FoundByUser     *User     `bson:"-" json:"foundByUser,omitempty"`
+	LostPersonUser  *User     `bson:"-" json:"lostPersonUser,omitempty"`
+	LostItemDetails *LostItem `bson:"-" json:"lostItemDetails,omitempty"`
+	// ContactPhone is FoundPersonPhone, filled in only for the finder and
+	// an approved claimant
+	ContactPhone string `bson:"-" json:"foundPersonPhone,omitempty" form:"-"`
 }
 
 // In FoundItem model file
@@ -33,3 +50,20 @@ func (f *FoundItem) ImageURL() string {
 func (f *FoundItem) FoundBy() primitive.ObjectID {
 	return f.FoundPerson
 }
+
+// SetImages replaces the item's images and points the single-image fields
+// at the primary one.
+func (f *FoundItem) SetImages(images []Image) {
+	NormalizeImages(images)
+	primary := PrimaryImage(images)
+	f.Images = images
+	f.Image = primary.URL
+	f.ImageMedium = primary.Medium
+	f.ImageThumbnail = primary.Thumbnail
+}
+
+// ImageList returns a copy of the item's images, including the single
+// image of reports created before they could have several.
+func (f *FoundItem) ImageList() []Image {
+	return legacyImages(f.Images, f.Image, f.ImageMedium, f.ImageThumbnail)
+}
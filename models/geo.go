@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// GeoPoint is a GeoJSON point. Coordinates are [longitude, latitude], the
+// order MongoDB's 2dsphere index expects.
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// NewGeoPoint validates lat/lng and returns the matching GeoJSON point.
+func NewGeoPoint(lat, lng float64) (*GeoPoint, error) {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("latitude %v out of range [-90, 90]", lat)
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("longitude %v out of range [-180, 180]", lng)
+	}
+	return &GeoPoint{Type: "Point", Coordinates: []float64{lng, lat}}, nil
+}
+
+func (p *GeoPoint) Lat() float64 { return p.Coordinates[1] }
+func (p *GeoPoint) Lng() float64 { return p.Coordinates[0] }
+
+// EarthRadiusMeters is the radius MongoDB uses for spherical distances.
+const EarthRadiusMeters = 6378.1 * 1000
+
+// DistanceTo returns the great-circle distance in meters between p and q.
+func (p *GeoPoint) DistanceTo(q *GeoPoint) float64 {
+	lat1, lat2 := p.Lat()*math.Pi/180, q.Lat()*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (q.Lng() - p.Lng()) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
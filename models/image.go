@@ -0,0 +1,73 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxImages is how many images a lost or found report can have.
+const MaxImages = 10
+
+// Image is one photo of a lost or found item. The images of a report are
+// ordered, and exactly one of them is primary: the one shown in listings.
+type Image struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	// Key locates the original in the image store; the variants are
+	// derived from it
+	Key       string `bson:"key,omitempty" json:"-"`
+	URL       string `bson:"url" json:"url"`
+	Medium    string `bson:"medium,omitempty" json:"medium,omitempty"`
+	Thumbnail string `bson:"thumbnail,omitempty" json:"thumbnail,omitempty"`
+	Caption   string `bson:"caption,omitempty" json:"caption,omitempty"`
+	Primary   bool   `bson:"primary" json:"primary"`
+	// Hash is the perceptual hash of the photo in hex, used to find items
+	// that look alike. Images uploaded before hashing have none.
+	Hash string `bson:"hash,omitempty" json:"-"`
+}
+
+// NormalizeImages leaves exactly one image primary: the first one flagged,
+// or the first image when none is.
+func NormalizeImages(images []Image) {
+	primary := -1
+	for i := range images {
+		if images[i].Primary && primary < 0 {
+			primary = i
+		}
+		images[i].Primary = false
+	}
+	if len(images) == 0 {
+		return
+	}
+	if primary < 0 {
+		primary = 0
+	}
+	images[primary].Primary = true
+}
+
+// PrimaryImage returns the primary image, or the zero Image when there are
+// no images.
+func PrimaryImage(images []Image) Image {
+	for _, image := range images {
+		if image.Primary {
+			return image
+		}
+	}
+	if len(images) > 0 {
+		return images[0]
+	}
+	return Image{}
+}
+
+// legacyImages returns images, or for reports created before they could
+// have several, a single image made from the old image fields.
+func legacyImages(images []Image, url, medium, thumbnail string) []Image {
+	if len(images) > 0 || url == "" {
+		return append([]Image(nil), images...)
+	}
+	return []Image{{
+		ID:        primitive.NewObjectID(),
+		URL:       url,
+		Medium:    medium,
+		Thumbnail: thumbnail,
+		Primary:   true,
+	}}
+}
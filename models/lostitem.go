@@ -12,14 +12,100 @@ type LostItem struct {
 	Description string             `bson:"description" json:"description"`
 	Category    string             `bson:"category" json:"category"`
 	ImageURL    string             `bson:"image" json:"image"` // Assuming this is intended as image URL
-	District    string             `bson:"district" json:"district"`
-	State       string             `bson:"state" json:"state"`
+	// ImageURL, ImageMedium and ImageThumbnail mirror the primary image for
+	// clients that only show one
+	ImageMedium    string `bson:"imageMedium,omitempty" json:"imageMedium,omitempty"`
+	ImageThumbnail string `bson:"imageThumbnail,omitempty" json:"imageThumbnail,omitempty"`
+	District       string `bson:"district" json:"district"`
+	State          string `bson:"state" json:"state"`
+
+	Images []Image `bson:"images,omitempty" json:"images,omitempty" form:"-"`
+	// ImagesVersion counts the changes to Images, so that concurrent image
+	// edits can detect each other
+	ImagesVersion int `bson:"imagesVersion,omitempty" json:"-" form:"-"`
 
 	Locations []string `bson:"locations,omitempty" json:"locations,omitempty"` // ✅ NEW FIELD
 
-	DateLost      time.Time          `bson:"dateLost" json:"dateLost"`
-	CreatedBy     primitive.ObjectID `bson:"user" json:"createdBy"`
-	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
-	CreatedByUser *User              `bson:"-" json:"createdByUser,omitempty"`
+	Position *GeoPoint `bson:"position,omitempty" json:"position,omitempty"` // Where the item was lost
+	Distance *float64  `bson:"-" json:"distance,omitempty"`                  // Meters from a near= search, response only
+	Score    *float64  `bson:"-" json:"score,omitempty"`                     // Relevance from a q= search, response only
+
+	DateLost      time.Time              `bson:"dateLost" json:"dateLost"`
+	Status        LostItemStatus         `bson:"status,omitempty" json:"status"`
+	StatusHistory []LostItemStatusChange `bson:"statusHistory,omitempty" json:"statusHistory,omitempty"`
+	Hidden        *ModerationAction      `bson:"hidden,omitempty" json:"hidden,omitempty"` // Set while a moderator hides the report
+	CreatedBy     primitive.ObjectID     `bson:"createdBy" json:"createdBy"`
+	CreatedAt     time.Time              `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time              `bson:"updatedAt" json:"updatedAt"`
+	CreatedByUser *User                  `bson:"-" json:"createdByUser,omitempty"`
+}
+
+type LostItemStatus string
+
+const (
+	LostItemOpen      LostItemStatus = "open"
+	LostItemMatched   LostItemStatus = "matched"
+	LostItemRecovered LostItemStatus = "recovered"
+	LostItemClosed    LostItemStatus = "closed"
+	LostItemExpired   LostItemStatus = "expired"
+)
+
+// lostItemTransitions lists the statuses each status may move to.
+var lostItemTransitions = map[LostItemStatus][]LostItemStatus{
+	LostItemOpen:      {LostItemMatched, LostItemRecovered, LostItemClosed, LostItemExpired},
+	LostItemMatched:   {LostItemOpen, LostItemRecovered, LostItemClosed, LostItemExpired},
+	LostItemRecovered: {LostItemClosed},
+	LostItemClosed:    {LostItemOpen},
+	LostItemExpired:   {LostItemOpen, LostItemClosed},
+}
+
+// Valid reports whether s is a known status.
+func (s LostItemStatus) Valid() bool {
+	_, ok := lostItemTransitions[s]
+	return ok
+}
+
+// CanTransition reports whether an item may move from s to next.
+func (s LostItemStatus) CanTransition(next LostItemStatus) bool {
+	for _, allowed := range lostItemTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// LostItemStatusChange records one transition in a LostItem's lifecycle.
+type LostItemStatusChange struct {
+	From LostItemStatus     `bson:"from" json:"from"`
+	To   LostItemStatus     `bson:"to" json:"to"`
+	At   time.Time          `bson:"at" json:"at"`
+	By   primitive.ObjectID `bson:"by,omitempty" json:"by,omitempty"`
+	Note string             `bson:"note,omitempty" json:"note,omitempty"`
+}
+
+// CurrentStatus returns the item's status, treating reports created before
+// statuses existed as open.
+func (l *LostItem) CurrentStatus() LostItemStatus {
+	if l.Status == "" {
+		return LostItemOpen
+	}
+	return l.Status
+}
+
+// SetImages replaces the item's images and points the single-image fields
+// at the primary one.
+func (l *LostItem) SetImages(images []Image) {
+	NormalizeImages(images)
+	primary := PrimaryImage(images)
+	l.Images = images
+	l.ImageURL = primary.URL
+	l.ImageMedium = primary.Medium
+	l.ImageThumbnail = primary.Thumbnail
+}
+
+// ImageList returns a copy of the item's images, including the single
+// image of reports created before they could have several.
+func (l *LostItem) ImageList() []Image {
+	return legacyImages(l.Images, l.ImageURL, l.ImageMedium, l.ImageThumbnail)
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchBreakdown holds the per-signal scores, each in [0, 1], that make up Match.Score.
+type MatchBreakdown struct {
+	Category float64 `bson:"category" json:"category"`
+	Location float64 `bson:"location" json:"location"`
+	Date     float64 `bson:"date" json:"date"`
+	Text     float64 `bson:"text" json:"text"`
+}
+
+// Match is a candidate pairing of a LostItem with a FoundItem that may be the same object.
+type Match struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LostItem  primitive.ObjectID `bson:"lostItem" json:"lostItem"`
+	FoundItem primitive.ObjectID `bson:"foundItem" json:"foundItem"`
+	Score     float64            `bson:"score" json:"score"`
+	Breakdown MatchBreakdown     `bson:"breakdown" json:"breakdown"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+
+	// Response only (not stored in DB)
+	LostItemDetails  *LostItem  `bson:"-" json:"lostItemDetails,omitempty"`
+	FoundItemDetails *FoundItem `bson:"-" json:"foundItemDetails,omitempty"`
+}
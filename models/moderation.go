@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Staff reports whether r may moderate other users' content.
+func (r Role) Staff() bool {
+	return r == RoleModerator || r == RoleAdmin
+}
+
+// Below reports whether r grants less than other.
+func (r Role) Below(other Role) bool {
+	return r.rank() < other.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleModerator:
+		return 1
+	case RoleAdmin:
+		return 2
+	}
+	return 0
+}
+
+// ModerationAction records who took a moderation action, when and why.
+type ModerationAction struct {
+	By     primitive.ObjectID `bson:"by" json:"by"`
+	At     time.Time          `bson:"at" json:"at"`
+	Reason string             `bson:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// Suspension keeps a user from signing in until Until, or indefinitely
+// when Until is zero.
+type Suspension struct {
+	ModerationAction `bson:",inline"`
+	Until            time.Time `bson:"until,omitempty" json:"until,omitempty"`
+}
+
+// Active reports whether the suspension is still in force at now.
+func (s *Suspension) Active(now time.Time) bool {
+	return s != nil && (s.Until.IsZero() || now.Before(s.Until))
+}
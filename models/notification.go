@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationType string
+
+const (
+	NotificationItemFound      NotificationType = "item_found"
+	NotificationItemReturned   NotificationType = "item_returned"
+	NotificationMatch          NotificationType = "match"
+	NotificationClaimCreated   NotificationType = "claim_created"
+	NotificationClaimQuestion  NotificationType = "claim_question"
+	NotificationClaimAnswered  NotificationType = "claim_answered"
+	NotificationClaimApproved  NotificationType = "claim_approved"
+	NotificationClaimRejected  NotificationType = "claim_rejected"
+	NotificationClaimWithdrawn NotificationType = "claim_withdrawn"
+	NotificationMessage        NotificationType = "message"
+	NotificationModeration     NotificationType = "moderation"
+	// NotificationGeneral covers notifications moved over from the old
+	// embedded user array, which recorded no type.
+	NotificationGeneral NotificationType = "general"
+)
+
+// Notification tells User about something another user (Actor) did. The
+// related item IDs link it to the lost and found items involved, if any.
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	User      primitive.ObjectID `bson:"user" json:"user"`
+	Type      NotificationType   `bson:"type" json:"type"`
+	Actor     primitive.ObjectID `bson:"actor,omitempty" json:"actor,omitempty"`
+	LostItem  primitive.ObjectID `bson:"lostItem,omitempty" json:"lostItem,omitempty"`
+	FoundItem primitive.ObjectID `bson:"foundItem,omitempty" json:"foundItem,omitempty"`
+	Message   string             `bson:"message" json:"message"`
+	Read      bool               `bson:"read" json:"read"`
+	ReadAt    *time.Time         `bson:"readAt,omitempty" json:"readAt,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
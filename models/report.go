@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TargetType names the kind of document a report or moderation action is about.
+type TargetType string
+
+const (
+	TargetLostItem  TargetType = "lost_item"
+	TargetFoundItem TargetType = "found_item"
+	TargetUser      TargetType = "user"
+	TargetMessage   TargetType = "message"
+)
+
+// Valid reports whether t can be reported.
+func (t TargetType) Valid() bool {
+	switch t {
+	case TargetLostItem, TargetFoundItem, TargetUser, TargetMessage:
+		return true
+	}
+	return false
+}
+
+type ReportReason string
+
+const (
+	ReportSpam         ReportReason = "spam"
+	ReportScam         ReportReason = "scam"
+	ReportFake         ReportReason = "fake"
+	ReportOffensive    ReportReason = "offensive"
+	ReportImage        ReportReason = "inappropriate_image"
+	ReportHarassment   ReportReason = "harassment"
+	ReportPersonalInfo ReportReason = "personal_info"
+	ReportOther        ReportReason = "other"
+)
+
+// Valid reports whether r is a known reason code.
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReportSpam, ReportScam, ReportFake, ReportOffensive, ReportImage,
+		ReportHarassment, ReportPersonalInfo, ReportOther:
+		return true
+	}
+	return false
+}
+
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportResolved  ReportStatus = "resolved"  // a moderator acted on it
+	ReportDismissed ReportStatus = "dismissed" // a moderator found nothing wrong
+)
+
+// Valid reports whether s is a known status.
+func (s ReportStatus) Valid() bool {
+	return s == ReportOpen || s == ReportResolved || s == ReportDismissed
+}
+
+// Report is a user's complaint about a lost or found item, another user or
+// a message. TargetOwner is whoever posted the target (the user themself for
+// a user report), so moderators can see who a queue entry is about.
+type Report struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TargetType  TargetType         `bson:"targetType" json:"targetType"`
+	Target      primitive.ObjectID `bson:"target" json:"target"`
+	TargetOwner primitive.ObjectID `bson:"targetOwner,omitempty" json:"targetOwner,omitempty"`
+	Reporter    primitive.ObjectID `bson:"reporter" json:"reporter"`
+	Reason      ReportReason       `bson:"reason" json:"reason"`
+	Details     string             `bson:"details,omitempty" json:"details,omitempty"`
+	Status      ReportStatus       `bson:"status" json:"status"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	// Set when a moderator resolves or dismisses the report
+	Decision *ModerationAction `bson:"decision,omitempty" json:"decision,omitempty"`
+}
+
+type AuditAction string
+
+const (
+	AuditHide           AuditAction = "hide"
+	AuditUnhide         AuditAction = "unhide"
+	AuditAutoHide       AuditAction = "auto_hide"
+	AuditDelete         AuditAction = "delete"
+	AuditSuspend        AuditAction = "suspend"
+	AuditUnsuspend      AuditAction = "unsuspend"
+	AuditSetRole        AuditAction = "set_role"
+	AuditResolveReports AuditAction = "resolve_reports"
+	AuditDismissReports AuditAction = "dismiss_reports"
+)
+
+// AuditEntry records one moderation decision. Actor is zero for actions the
+// system took on its own, such as hiding heavily reported content.
+type AuditEntry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Actor      primitive.ObjectID `bson:"actor,omitempty" json:"actor,omitempty"`
+	Action     AuditAction        `bson:"action" json:"action"`
+	TargetType TargetType         `bson:"targetType" json:"targetType"`
+	Target     primitive.ObjectID `bson:"target" json:"target"`
+	Note       string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
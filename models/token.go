@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is the server-side record of an opaque refresh token. Every
+// refresh rotates the token: the old one is marked used and a new one is
+// issued in the same Family. Presenting a used token again means it was
+// stolen, and the whole family is revoked.
+type RefreshToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	User       primitive.ObjectID `bson:"user" json:"user"`
+	Family     primitive.ObjectID `bson:"family" json:"family"`
+	TokenHash  string             `bson:"tokenHash" json:"-"`
+	ExpiresAt  time.Time          `bson:"expiresAt" json:"expiresAt"`
+	UsedAt     time.Time          `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+	ReplacedBy primitive.ObjectID `bson:"replacedBy,omitempty" json:"replacedBy,omitempty"`
+	RevokedAt  time.Time          `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// RevokedToken blocks access tokens before they expire, either one token by
+// JTI or every token of a session by Family. Entries are only needed until
+// the tokens they cover would have expired anyway.
+type RevokedToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	JTI       string             `bson:"jti,omitempty" json:"jti,omitempty"`
+	Family    primitive.ObjectID `bson:"family,omitempty" json:"family,omitempty"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt"`
+}
+
+type TokenPurpose string
+
+const (
+	TokenPasswordReset     TokenPurpose = "password_reset"
+	TokenEmailVerification TokenPurpose = "email_verification"
+)
+
+// OneTimeToken is the server-side record of a token mailed to a user, such
+// as a password reset link. Only the hash is stored; a token works once and
+// only until ExpiresAt.
+type OneTimeToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	User      primitive.ObjectID `bson:"user" json:"user"`
+	Purpose   TokenPurpose       `bson:"purpose" json:"purpose"`
+	TokenHash string             `bson:"tokenHash" json:"-"`
+	// Email is the address an email verification token confirms
+	Email     string    `bson:"email,omitempty" json:"email,omitempty"`
+	ExpiresAt time.Time `bson:"expiresAt" json:"expiresAt"`
+	UsedAt    time.Time `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
@@ -11,21 +11,30 @@ type Location struct {
 	State    string `bson:"state" json:"state"`
 }
 
-type Notification struct {
-	Message   string    `bson:"message" json:"message"`
-	Read      bool      `bson:"read" json:"read"`
-	CreatedAt time.Time `bson:"createdAt,omitempty" json:"createdAt"`
+type User struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username string             `bson:"username" json:"username"`
+	Email    string             `bson:"email" json:"email"`
+	// EmailVerified is set once the user confirms Email from a mailed link.
+	// A changed address waits in PendingEmail until it is confirmed the same way.
+	EmailVerified bool     `bson:"emailVerified" json:"emailVerified"`
+	PendingEmail  string   `bson:"pendingEmail,omitempty" json:"pendingEmail,omitempty"`
+	Phone         string   `bson:"phone" json:"phone"`
+	Profession    string   `bson:"profession" json:"profession"`
+	Location      Location `bson:"location" json:"location"`
+	Password      string   `bson:"password" json:"password"`
+	// Role is missing on accounts created before roles existed; see CurrentRole
+	Role       Role        `bson:"role,omitempty" json:"role"`
+	Suspension *Suspension `bson:"suspension,omitempty" json:"suspension,omitempty"`
+	CreatedAt  time.Time   `bson:"createdAt,omitempty" json:"createdAt"`
+	UpdatedAt  time.Time   `bson:"updatedAt,omitempty" json:"updatedAt"`
 }
 
-type User struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Username      string             `bson:"username" json:"username"`
-	Email         string             `bson:"email" json:"email"`
-	Phone         string             `bson:"phone" json:"phone"`
-	Profession    string             `bson:"profession" json:"profession"`
-	Location      Location           `bson:"location" json:"location"`
-	Password      string             `bson:"password" json:"password"`
-	Notifications []Notification     `bson:"notifications,omitempty" json:"notifications"`
-	CreatedAt     time.Time          `bson:"createdAt,omitempty" json:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt,omitempty" json:"updatedAt"`
+// CurrentRole returns the user's role, treating accounts created before
+// roles existed as regular users.
+func (u *User) CurrentRole() Role {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
 }
@@ -0,0 +1,130 @@
+// Package notify fans events out to the live connections of each user.
+// Events are only delivered to connections open when they are published;
+// replaying what a reconnecting client missed is up to the caller, which
+// reads it back from wherever the events are stored.
+package notify
+
+import (
+	"sync"
+)
+
+// Event is one message delivered to a user's streams. ID is chosen by the
+// publisher and is what a client sends back as Last-Event-ID to resume.
+type Event struct {
+	ID   string
+	Type string
+	Data interface{}
+}
+
+// subscriberBuffer is how many undelivered events a subscription may queue
+// before it is considered too slow and dropped.
+const subscriberBuffer = 16
+
+// Hub routes published events to every subscription of the target user. It
+// is safe for concurrent use by any number of publishers and subscribers.
+type Hub struct {
+	mu sync.Mutex
+	// users has an entry only while the user has a subscription, so it
+	// stays as small as the number of connected users
+	users map[string]*userEvents
+}
+
+type userEvents struct {
+	subs map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{users: map[string]*userEvents{}}
+}
+
+// drop unregisters sub and closes its channel, removing the user's entry
+// once it has no subscriptions left. Callers must hold h.mu.
+func (h *Hub) drop(u *userEvents, sub *Subscription) {
+	delete(u.subs, sub)
+	close(sub.events)
+	if len(u.subs) == 0 {
+		delete(h.users, sub.userID)
+	}
+}
+
+// Publish delivers the event to each of the user's subscriptions. A
+// subscription whose buffer is full is closed rather than allowed to block
+// the publisher; its client is expected to reconnect and resume.
+func (h *Hub) Publish(userID string, event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	u, ok := h.users[userID]
+	if !ok {
+		return
+	}
+	for sub := range u.subs {
+		select {
+		case sub.events <- event:
+		default:
+			h.drop(u, sub)
+		}
+	}
+}
+
+// Subscribe registers a new subscription for userID, opened under session.
+// Events published from now on are delivered to it, so a caller replaying
+// missed events should subscribe first and then read them, skipping any
+// delivered twice.
+func (h *Hub) Subscribe(userID, session string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{hub: h, userID: userID, session: session, events: make(chan Event, subscriberBuffer)}
+	u, ok := h.users[userID]
+	if !ok {
+		u = &userEvents{subs: map[*Subscription]struct{}{}}
+		h.users[userID] = u
+	}
+	u.subs[sub] = struct{}{}
+	return sub
+}
+
+// CloseSession closes every subscription opened under session, e.g. once
+// the session is logged out or revoked.
+func (h *Hub) CloseSession(session string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, u := range h.users {
+		for sub := range u.subs {
+			if sub.session == session {
+				h.drop(u, sub)
+			}
+		}
+	}
+}
+
+// Subscription is one live connection's view of a user's events.
+type Subscription struct {
+	hub     *Hub
+	userID  string
+	session string
+	events  chan Event
+}
+
+// Events delivers the subscription's events. It is closed when the
+// subscription is closed, its session is closed or it is dropped for falling
+// behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close unregisters the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	u, ok := s.hub.users[s.userID]
+	if !ok {
+		return
+	}
+	if _, ok := u.subs[s]; ok {
+		s.hub.drop(u, s)
+	}
+}
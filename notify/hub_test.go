@@ -0,0 +1,99 @@
+package notify
+
+import "testing"
+
+func TestHubPublish(t *testing.T) {
+	h := NewHub()
+	alice, bob := h.Subscribe("alice", "s1"), h.Subscribe("bob", "s2")
+	defer bob.Close()
+
+	h.Publish("alice", Event{ID: "1", Type: "notification"})
+	select {
+	case event := <-alice.Events():
+		if event.ID != "1" {
+			t.Errorf("alice got event %q, want 1", event.ID)
+		}
+	default:
+		t.Fatal("alice did not get the event")
+	}
+	select {
+	case event := <-bob.Events():
+		t.Errorf("bob got alice's event %q", event.ID)
+	default:
+	}
+
+	// Only events published while subscribed are delivered
+	alice.Close()
+	alice.Close()
+	h.Publish("alice", Event{ID: "2"})
+	if _, ok := <-alice.Events(); ok {
+		t.Error("a closed subscription still delivers events")
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe("alice", "s1")
+	defer sub.Close()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		h.Publish("alice", Event{Type: "notification"})
+	}
+	n := 0
+	for range sub.Events() {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("slow subscriber got %d events before being dropped, want %d", n, subscriberBuffer)
+	}
+}
+
+func TestHubForgetsIdleUsers(t *testing.T) {
+	h := NewHub()
+	first, second := h.Subscribe("alice", "s1"), h.Subscribe("alice", "s1")
+	h.Publish("bob", Event{ID: "1"})
+	if len(h.users) != 1 {
+		t.Fatalf("hub tracks %d users, want 1", len(h.users))
+	}
+
+	first.Close()
+	if len(h.users) != 1 {
+		t.Errorf("user forgotten while still subscribed")
+	}
+	second.Close()
+	if len(h.users) != 0 {
+		t.Errorf("hub tracks %d users after every subscription closed, want 0", len(h.users))
+	}
+
+	// A subscriber dropped for falling behind is forgotten too
+	slow := h.Subscribe("alice", "s1")
+	for i := 0; i <= subscriberBuffer; i++ {
+		h.Publish("alice", Event{})
+	}
+	if len(h.users) != 0 {
+		t.Errorf("hub tracks %d users after dropping the last subscriber, want 0", len(h.users))
+	}
+	slow.Close()
+}
+
+func TestHubCloseSession(t *testing.T) {
+	h := NewHub()
+	phone, laptop := h.Subscribe("alice", "phone"), h.Subscribe("alice", "laptop")
+	defer laptop.Close()
+
+	h.CloseSession("phone")
+	if _, ok := <-phone.Events(); ok {
+		t.Error("subscription of a closed session still open")
+	}
+	phone.Close()
+
+	h.Publish("alice", Event{ID: "1"})
+	select {
+	case event := <-laptop.Events():
+		if event.ID != "1" {
+			t.Errorf("other session got event %q, want 1", event.ID)
+		}
+	default:
+		t.Error("closing one session closed the user's other sessions")
+	}
+}
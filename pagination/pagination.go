@@ -0,0 +1,150 @@
+// Package pagination splits long listings into pages. Each page comes with an
+// opaque cursor the client sends back for the next one. Listings in creation
+// order use keyset cursors holding the createdAt and _id of the last item, so
+// pages stay stable while new items arrive; listings sorted by distance or
+// relevance fall back to cursors holding an offset.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultLimit is the page size when the client does not ask for one.
+	DefaultLimit = 20
+	// MaxLimit caps the page size a client can ask for.
+	MaxLimit = 100
+)
+
+var (
+	ErrInvalidLimit  = errors.New("limit must be a positive number")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Cursor marks where a page ended: after the item created at CreatedAt with
+// ID, or after Offset items for listings not in creation order.
+type Cursor struct {
+	CreatedAt time.Time          `json:"t,omitempty"`
+	ID        primitive.ObjectID `json:"id,omitempty"`
+	Offset    int64              `json:"o,omitempty"`
+}
+
+// After returns the keyset cursor of an item.
+func After(createdAt time.Time, id primitive.ObjectID) Cursor {
+	return Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// Keyset reports whether c is a keyset cursor rather than an offset.
+func (c Cursor) Keyset() bool {
+	return !c.ID.IsZero()
+}
+
+// Encode returns the token handed to clients.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode reads a token returned by Encode.
+func Decode(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 || (c.Keyset() && c.Offset != 0) {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Page is a client's request for one page of a listing.
+type Page struct {
+	Limit int64
+	// Cursor is where the previous page ended, nil for the first page
+	Cursor *Cursor
+	// Count asks for the total number of items in the listing
+	Count bool
+}
+
+// Parse reads the limit, cursor and count query parameters. keyset says
+// whether the listing is in creation order, which decides the kind of
+// cursor it accepts.
+func Parse(limit, cursor, count string, keyset bool) (Page, error) {
+	page := Page{Limit: DefaultLimit, Count: count == "true"}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return Page{}, ErrInvalidLimit
+		}
+		page.Limit = int64(min(n, MaxLimit))
+	}
+	if cursor != "" {
+		c, err := Decode(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		if c.Keyset() != keyset {
+			return Page{}, ErrInvalidCursor
+		}
+		page.Cursor = c
+	}
+	return page, nil
+}
+
+// After returns the keyset cursor the listing continues after, or nil on
+// the first page and for offset cursors.
+func (p Page) After() *Cursor {
+	if p.Cursor == nil || !p.Cursor.Keyset() {
+		return nil
+	}
+	return p.Cursor
+}
+
+// Skip returns how many items an offset cursor skips.
+func (p Page) Skip() int64 {
+	if p.Cursor == nil {
+		return 0
+	}
+	return p.Cursor.Offset
+}
+
+// Fetch is how many items to load: one more than the page holds, which
+// tells whether there is a next page.
+func (p Page) Fetch() int64 {
+	return p.Limit + 1
+}
+
+// Result is the response body of a paginated listing.
+type Result[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor fetches the next page; it is left out on the last one
+	NextCursor string `json:"nextCursor,omitempty"`
+	// TotalCount is only filled in when the client asked for it
+	TotalCount *int64 `json:"totalCount,omitempty"`
+}
+
+// NewResult builds the page from items loaded with Fetch. key returns the
+// keyset cursor of an item; pass nil for listings paged by offset.
+func NewResult[T any](p Page, items []T, key func(T) Cursor) Result[T] {
+	if items == nil {
+		items = []T{}
+	}
+	result := Result[T]{Items: items}
+	if int64(len(items)) <= p.Limit {
+		return result
+	}
+	result.Items = items[:p.Limit]
+	next := Cursor{Offset: p.Skip() + p.Limit}
+	if key != nil {
+		next = key(result.Items[len(result.Items)-1])
+	}
+	result.NextCursor = next.Encode()
+	return result
+}
@@ -0,0 +1,126 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParse(t *testing.T) {
+	keyset := After(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), primitive.NewObjectID()).Encode()
+	offset := Cursor{Offset: 40}.Encode()
+
+	tests := []struct {
+		name                 string
+		limit, cursor, count string
+		keyset               bool
+		want                 Page
+		wantErr              error
+	}{
+		{name: "defaults", keyset: true, want: Page{Limit: DefaultLimit}},
+		{name: "limit", limit: "5", keyset: true, want: Page{Limit: 5}},
+		{name: "limit capped", limit: "1000", keyset: true, want: Page{Limit: MaxLimit}},
+		{name: "count", count: "true", keyset: true, want: Page{Limit: DefaultLimit, Count: true}},
+		{name: "count other value", count: "1", keyset: true, want: Page{Limit: DefaultLimit}},
+		{name: "zero limit", limit: "0", keyset: true, wantErr: ErrInvalidLimit},
+		{name: "negative limit", limit: "-3", keyset: true, wantErr: ErrInvalidLimit},
+		{name: "word limit", limit: "ten", keyset: true, wantErr: ErrInvalidLimit},
+		{name: "garbage cursor", cursor: "%%%", keyset: true, wantErr: ErrInvalidCursor},
+		{name: "offset cursor on keyset listing", cursor: offset, keyset: true, wantErr: ErrInvalidCursor},
+		{name: "keyset cursor on offset listing", cursor: keyset, keyset: false, wantErr: ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.limit, tt.cursor, tt.count, tt.keyset)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Limit != tt.want.Limit || got.Count != tt.want.Count || got.Cursor != nil) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	id := primitive.NewObjectID()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	page, err := Parse("", After(at, id).Encode(), "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after := page.After(); after == nil || !after.CreatedAt.Equal(at) || after.ID != id {
+		t.Errorf("After() = %+v, want createdAt %v and id %v", after, at, id)
+	}
+	if page.Skip() != 0 {
+		t.Errorf("Skip() = %d for a keyset cursor", page.Skip())
+	}
+
+	page, err = Parse("", Cursor{Offset: 40}.Encode(), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.After() != nil {
+		t.Errorf("After() = %+v for an offset cursor", page.After())
+	}
+	if page.Skip() != 40 {
+		t.Errorf("Skip() = %d, want 40", page.Skip())
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	for name, token := range map[string]string{
+		"not base64":        "***",
+		"not json":          "bm90IGpzb24",
+		"negative offset":   Cursor{Offset: -1}.Encode(),
+		"offset and keyset": Cursor{ID: primitive.NewObjectID(), Offset: 3}.Encode(),
+	} {
+		if _, err := Decode(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: Decode() error = %v, want ErrInvalidCursor", name, err)
+		}
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	key := func(n int) Cursor { return Cursor{ID: ids[n-1]} }
+
+	tests := []struct {
+		name       string
+		page       Page
+		items      []int
+		key        func(int) Cursor
+		wantItems  int
+		wantCursor *Cursor
+	}{
+		{name: "empty", page: Page{Limit: 2}, wantItems: 0},
+		{name: "last page", page: Page{Limit: 2}, items: []int{1, 2}, key: key, wantItems: 2},
+		{name: "keyset next", page: Page{Limit: 2}, items: []int{1, 2, 3}, key: key, wantItems: 2, wantCursor: &Cursor{ID: ids[1]}},
+		{name: "offset next", page: Page{Limit: 2}, items: []int{1, 2, 3}, wantItems: 2, wantCursor: &Cursor{Offset: 2}},
+		{name: "offset after offset", page: Page{Limit: 2, Cursor: &Cursor{Offset: 4}}, items: []int{5, 6, 7}, wantItems: 2, wantCursor: &Cursor{Offset: 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewResult(tt.page, tt.items, tt.key)
+			if result.Items == nil || len(result.Items) != tt.wantItems {
+				t.Fatalf("Items = %v, want %d items", result.Items, tt.wantItems)
+			}
+			if tt.wantCursor == nil {
+				if result.NextCursor != "" {
+					t.Errorf("NextCursor = %q on the last page", result.NextCursor)
+				}
+				return
+			}
+			next, err := Decode(result.NextCursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *next != *tt.wantCursor {
+				t.Errorf("NextCursor = %+v, want %+v", next, tt.wantCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,445 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// suspendedResponse rejects a request from a suspended user.
+func suspendedResponse(c *gin.Context, suspension *models.Suspension) {
+	response := gin.H{"error": "Account suspended"}
+	if suspension.Reason != "" {
+		response["reason"] = suspension.Reason
+	}
+	if !suspension.Until.IsZero() {
+		response["until"] = suspension.Until
+	}
+	c.JSON(http.StatusForbidden, response)
+}
+
+// canSeeHidden reports whether the current user may see a hidden item
+// reported by owner: the owner and staff can.
+func canSeeHidden(c *gin.Context, owner primitive.ObjectID) bool {
+	if currentRole(c).Staff() {
+		return true
+	}
+	userID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	return err == nil && userID == owner
+}
+
+// hiddenFrom reports whether an item reported by owner is hidden from the
+// current user.
+func hiddenFrom(c *gin.Context, hidden *models.ModerationAction, owner primitive.ObjectID) bool {
+	return hidden != nil && !canSeeHidden(c, owner)
+}
+
+// parseAdminList reads the visibility parameter of the admin item listings
+// along with the page. On failure it writes the response and returns false.
+func parseAdminList(c *gin.Context) (visibility store.Visibility, page pagination.Page, ok bool) {
+	switch c.DefaultQuery("visibility", "all") {
+	case "all":
+		visibility = store.AllItems
+	case "visible":
+		visibility = store.VisibleItems
+	case "hidden":
+		visibility = store.HiddenItems
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "visibility must be all, visible or hidden"})
+		return 0, page, false
+	}
+	page, ok = parsePage(c, true)
+	return visibility, page, ok
+}
+
+// AdminListLostItems lists every lost item report, including hidden ones
+// unless ?visibility= says otherwise.
+func AdminListLostItems(c *gin.Context) {
+	visibility, page, ok := parseAdminList(c)
+	if !ok {
+		return
+	}
+	filter := store.LostItemFilter{Visibility: visibility, After: page.After(), Limit: page.Fetch()}
+	result, err := loadPage(page, lostItemCursor,
+		func() ([]models.LostItem, error) { return store.LostItems.List(context.Background(), filter) },
+		func() (int64, error) { return store.LostItems.Count(context.Background(), filter) },
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// AdminListFoundItems lists every found item report, including hidden ones
+// unless ?visibility= says otherwise.
+func AdminListFoundItems(c *gin.Context) {
+	visibility, page, ok := parseAdminList(c)
+	if !ok {
+		return
+	}
+	filter := store.FoundItemFilter{Visibility: visibility, After: page.After(), Limit: page.Fetch()}
+	result, err := loadPage(page, foundItemCursor,
+		func() ([]models.FoundItem, error) { return store.FoundItems.List(context.Background(), filter) },
+		func() (int64, error) { return store.FoundItems.Count(context.Background(), filter) },
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// hideRequest is the body of the hide endpoints.
+type hideRequest struct {
+	Hidden *bool  `json:"hidden" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// AdminSetLostItemHidden hides a lost item report from everyone but its
+// owner and staff, or shows it again.
+func AdminSetLostItemHidden(c *gin.Context) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID format"})
+		return
+	}
+	var request hideRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	item, err := store.LostItems.FindByID(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	var hidden *models.ModerationAction
+	if *request.Hidden {
+		hidden = &models.ModerationAction{By: moderatorID, At: time.Now(), Reason: request.Reason}
+	}
+	if err := store.LostItems.SetHidden(context.Background(), objID, hidden); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+	recordAudit(moderatorID, hideAction(hidden), models.TargetLostItem, objID, request.Reason)
+
+	if hidden != nil {
+		notifyUser(models.Notification{
+			User:     item.CreatedBy,
+			Type:     models.NotificationModeration,
+			Actor:    moderatorID,
+			LostItem: objID,
+			Message:  moderationMessage("Your lost item report \""+item.Name+"\" was hidden by a moderator", request.Reason),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item updated successfully", "hidden": *request.Hidden})
+}
+
+// AdminSetFoundItemHidden hides a found item report from everyone but its
+// finder and staff, or shows it again.
+func AdminSetFoundItemHidden(c *gin.Context) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID format"})
+		return
+	}
+	var request hideRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	item, err := store.FoundItems.FindByID(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	var hidden *models.ModerationAction
+	if *request.Hidden {
+		hidden = &models.ModerationAction{By: moderatorID, At: time.Now(), Reason: request.Reason}
+	}
+	if err := store.FoundItems.SetHidden(context.Background(), objID, hidden); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+	recordAudit(moderatorID, hideAction(hidden), models.TargetFoundItem, objID, request.Reason)
+
+	if hidden != nil {
+		notifyUser(models.Notification{
+			User:      item.FoundPerson,
+			Type:      models.NotificationModeration,
+			Actor:     moderatorID,
+			FoundItem: objID,
+			Message:   moderationMessage("Your found item report \""+item.Name+"\" was hidden by a moderator", request.Reason),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item updated successfully", "hidden": *request.Hidden})
+}
+
+// AdminDeleteLostItem removes any lost item report.
+func AdminDeleteLostItem(c *gin.Context) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID format"})
+		return
+	}
+
+	item, err := store.LostItems.FindByID(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	err = store.LostItems.Delete(context.Background(), objID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
+		return
+	}
+
+	if err := store.Matches.DeleteByLostItem(context.Background(), objID); err != nil {
+		log.Println("DeleteByLostItem error:", err)
+	}
+	deleteImages(item.ImageList())
+	recordAudit(moderatorID, models.AuditDelete, models.TargetLostItem, objID, c.Query("reason"))
+	notifyUser(models.Notification{
+		User:    item.CreatedBy,
+		Type:    models.NotificationModeration,
+		Actor:   moderatorID,
+		Message: moderationMessage("Your lost item report \""+item.Name+"\" was removed by a moderator", c.Query("reason")),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Item deleted successfully",
+		"itemId":  objID,
+	})
+}
+
+// AdminDeleteFoundItem removes any found item report.
+func AdminDeleteFoundItem(c *gin.Context) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID format"})
+		return
+	}
+
+	item, err := store.FoundItems.FindByID(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	err = store.FoundItems.Delete(context.Background(), objID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
+		return
+	}
+
+	if err := store.Matches.DeleteByFoundItem(context.Background(), objID); err != nil {
+		log.Println("DeleteByFoundItem error:", err)
+	}
+	deleteImages(item.ImageList())
+	recordAudit(moderatorID, models.AuditDelete, models.TargetFoundItem, objID, c.Query("reason"))
+	notifyUser(models.Notification{
+		User:    item.FoundPerson,
+		Type:    models.NotificationModeration,
+		Actor:   moderatorID,
+		Message: moderationMessage("Your found item report \""+item.Name+"\" was removed by a moderator", c.Query("reason")),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Item deleted successfully",
+		"itemId":  objID,
+	})
+}
+
+// SuspendUser suspends an account until a given time or indefinitely and
+// ends its sessions. Moderators can only suspend regular users.
+func SuspendUser(c *gin.Context) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+	var request struct {
+		Reason string `json:"reason" binding:"required"`
+		// Until is an RFC 3339 timestamp; without it the suspension lasts
+		// until lifted
+		Until *time.Time `json:"until"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	now := time.Now()
+	if request.Until != nil && !request.Until.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until must be in the future"})
+		return
+	}
+
+	if objID == moderatorID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot suspend yourself"})
+		return
+	}
+	user, err := store.Users.FindByID(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.CurrentRole().Staff() && currentRole(c) != models.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can suspend staff"})
+		return
+	}
+
+	suspension := &models.Suspension{
+		ModerationAction: models.ModerationAction{By: moderatorID, At: now, Reason: request.Reason},
+	}
+	if request.Until != nil {
+		suspension.Until = *request.Until
+	}
+	if err := store.Users.SetSuspension(context.Background(), objID, suspension); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suspend user"})
+		return
+	}
+	recordAudit(moderatorID, models.AuditSuspend, models.TargetUser, objID, request.Reason)
+	if err := revokeUserSessions(objID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "User suspended, but failed to end their sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended", "suspension": suspension})
+}
+
+// UnsuspendUser lifts a suspension.
+func UnsuspendUser(c *gin.Context) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+	err = store.Users.SetSuspension(context.Background(), objID, nil)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to lift suspension"})
+		return
+	}
+	recordAudit(moderatorID, models.AuditUnsuspend, models.TargetUser, objID, "")
+	c.JSON(http.StatusOK, gin.H{"message": "Suspension lifted"})
+}
+
+// SetUserRole changes a user's role. A promotion takes effect on the user's
+// next login or token refresh; a demotion ends their sessions so the old
+// role stops working at once.
+func SetUserRole(c *gin.Context) {
+	adminID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+	var request struct {
+		Role models.Role `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if !request.Role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role: " + string(request.Role)})
+		return
+	}
+	// Keeps the last admin from locking everyone out by accident
+	if objID == adminID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change your own role"})
+		return
+	}
+
+	user, err := store.Users.FindByID(context.Background(), objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	err = store.Users.SetRole(context.Background(), objID, request.Role)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+	recordAudit(adminID, models.AuditSetRole, models.TargetUser, objID, string(request.Role))
+	if request.Role.Below(user.CurrentRole()) {
+		if err := revokeUserSessions(objID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Role updated, but failed to end the user's sessions"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated", "role": request.Role})
+}
+
+// hideAction is the audit action for setting hidden.
+func hideAction(hidden *models.ModerationAction) models.AuditAction {
+	if hidden == nil {
+		return models.AuditUnhide
+	}
+	return models.AuditHide
+}
+
+// moderationMessage appends the moderator's reason, if any, to message.
+func moderationMessage(message, reason string) string {
+	if reason == "" {
+		return message
+	}
+	return message + ": " + reason
+}
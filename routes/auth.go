@@ -2,16 +2,16 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
-	"lostfound-backend/db"
 	"lostfound-backend/models"
-	"lostfound-backend/utils"
+	"lostfound-backend/store"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -31,25 +31,32 @@ func Signup(c *gin.Context) {
 		return
 	}
 	user.Password = string(hashedPassword)
-
-	collection := db.GetCollection("users")
-
-	var existingUser models.User
-	err = collection.FindOne(context.Background(), bson.M{"email": user.Email}).Decode(&existingUser)
-	if err == nil {
+	user.EmailVerified = false
+	user.PendingEmail = ""
+	user.Role = models.RoleUser
+	user.Suspension = nil
+
+	// The unique index on email rejects addresses already registered
+	err = store.Users.Insert(context.Background(), &user)
+	if mongo.IsDuplicateKeyError(err) {
 		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
 		return
 	}
-
-	_, err = collection.InsertOne(context.Background(), user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create account"})
 		return
 	}
 
+	// The account works right away; a failed mail only means the user has
+	// to ask for another link
+	message := "Registration successful. Check your email to verify your account"
+	if err := sendVerificationEmail(&user, user.Email); err != nil {
+		message = "Registration successful, but the verification email could not be sent"
+	}
+
 	user.Password = ""
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Registration successful",
+		"message": message,
 		"user":    user,
 	})
 }
@@ -69,9 +76,7 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("users")
-	var user models.User
-	err := collection.FindOne(context.Background(), bson.M{"email": input.Email}).Decode(&user)
+	user, err := store.Users.FindByEmail(context.Background(), input.Email)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
@@ -83,18 +88,21 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	token, err := utils.GenerateJWT(user.ID.Hex())
+	if user.Suspension.Active(time.Now()) {
+		suspendedResponse(c, user.Suspension)
+		return
+	}
+
+	response, err := issueTokens(user, primitive.NilObjectID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
 	user.Password = ""
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"user":    user,
-		"token":   token,
-	})
+	response["message"] = "Login successful"
+	response["user"] = user
+	c.JSON(http.StatusOK, response)
 }
 func CheckSession(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -103,15 +111,13 @@ func CheckSession(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("users")
 	objID, err := primitive.ObjectIDFromHex(userID.(string))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
 		return
 	}
 
-	var user models.User
-	err = collection.FindOne(context.Background(), bson.M{"_id": objID}).Decode(&user)
+	user, err := store.Users.FindByID(context.Background(), objID)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 		return
@@ -147,7 +153,7 @@ func UpdateUser(c *gin.Context) {
 
 	var updateData struct {
 		Username   string `json:"username"`
-		Email      string `json:"email"`
+		Email      string `json:"email" binding:"omitempty,email"`
 		Phone      string `json:"phone"`
 		Profession string `json:"profession"`
 		District   string `json:"district"`
@@ -165,46 +171,61 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("users")
-	update := bson.M{
-		"$set": bson.M{
-			"username":   updateData.Username,
-			"email":      updateData.Email,
-			"phone":      updateData.Phone,
-			"profession": updateData.Profession,
-			"district":   updateData.District,
-			"state":      updateData.State,
-			"updatedAt":  time.Now(),
-		},
+	// A new email address must not belong to another account
+	if updateData.Email != "" {
+		other, err := store.Users.FindByEmail(context.Background(), updateData.Email)
+		if err == nil && other.ID != objID {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+			return
+		}
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+			return
+		}
 	}
 
-	result, err := collection.UpdateOne(
-		context.Background(),
-		bson.M{"_id": objID},
-		update,
-	)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+	err = store.Users.UpdateProfile(context.Background(), objID, store.ProfileUpdate{
+		Username:   updateData.Username,
+		Phone:      updateData.Phone,
+		Profession: updateData.Profession,
+		District:   updateData.District,
+		State:      updateData.State,
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
-
-	if result.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
 		return
 	}
 
 	// Return the updated user data
-	var updatedUser models.User
-	err = collection.FindOne(context.Background(), bson.M{"_id": objID}).Decode(&updatedUser)
+	updatedUser, err := store.Users.FindByID(context.Background(), objID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated user"})
 		return
 	}
 
+	// The address only changes once the user confirms it from the link
+	// mailed to the new address; until then the old one stays in use
+	message := "Profile updated successfully"
+	if updateData.Email != "" && updateData.Email != updatedUser.Email {
+		if err := store.Users.SetPendingEmail(context.Background(), objID, updateData.Email); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email"})
+			return
+		}
+		updatedUser.PendingEmail = updateData.Email
+		if err := sendVerificationEmail(updatedUser, updateData.Email); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+			return
+		}
+		message = "Profile updated. Confirm your new email address from the link sent to it"
+	}
+
 	updatedUser.Password = "" // Don't return the password
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile updated successfully",
+		"message": message,
 		"user":    updatedUser,
 	})
 }
@@ -221,9 +242,7 @@ func GetUserProfile(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("users")
-	var user models.User
-	err = collection.FindOne(context.Background(), bson.M{"_id": objID}).Decode(&user)
+	user, err := store.Users.FindByID(context.Background(), objID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
@@ -2,17 +2,18 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
-	"lostfound-backend/db"
 	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func AddBookmark(c *gin.Context) {
@@ -55,8 +56,11 @@ func AddBookmark(c *gin.Context) {
 	}
 
 	// Insert into database
-	collection := db.GetCollection("bookmarks")
-	_, err = collection.InsertOne(context.Background(), bookmark)
+	err = store.Bookmarks.Insert(context.Background(), &bookmark)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Item already bookmarked"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bookmark"})
 		return
@@ -82,38 +86,29 @@ func GetBookmarks(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("bookmarks")
-	lookupStage := bson.D{
-		{Key: "$lookup", Value: bson.D{
-			{Key: "from", Value: "lostitems"},
-			{Key: "localField", Value: "lostItem"},
-			{Key: "foreignField", Value: "_id"},
-			{Key: "as", Value: "lostItem"},
-		}},
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
 	}
-	unwindStage := bson.D{{Key: "$unwind", Value: bson.D{
-		{Key: "path", Value: "$lostItem"},
-		{Key: "preserveNullAndEmptyArrays", Value: true},
-	}}}
-	matchStage := bson.D{{Key: "$match", Value: bson.D{
-		{Key: "user", Value: uid},
-	}}}
-
-	opts := options.Aggregate().SetMaxTime(5 * time.Second)
-	cursor, err := collection.Aggregate(context.Background(), mongo.Pipeline{matchStage, lookupStage, unwindStage}, opts)
+	filter := store.BookmarkFilter{User: uid, After: page.After(), Limit: page.Fetch()}
+	result, err := loadPage(page, bookmarkCursor,
+		func() ([]bson.M, error) { return store.Bookmarks.ListWithLostItems(context.Background(), filter) },
+		func() (int64, error) { return store.Bookmarks.Count(context.Background(), uid) },
+	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
-	defer cursor.Close(context.Background())
 
-	var results []bson.M
-	if err := cursor.All(context.Background(), &results); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Decode error"})
-		return
-	}
+	c.JSON(http.StatusOK, result)
+}
 
-	c.JSON(http.StatusOK, results)
+// bookmarkCursor is the keyset cursor of a bookmark returned by
+// ListWithLostItems.
+func bookmarkCursor(bookmark bson.M) pagination.Cursor {
+	createdAt, _ := bookmark["createdAt"].(primitive.DateTime)
+	id, _ := bookmark["_id"].(primitive.ObjectID)
+	return pagination.After(createdAt.Time(), id)
 }
 func DeleteBookmark(c *gin.Context) {
 	// Get user ID from context
@@ -143,19 +138,13 @@ func DeleteBookmark(c *gin.Context) {
 	}
 
 	// Delete the bookmark (only if it belongs to the user)
-	collection := db.GetCollection("bookmarks")
-	result, err := collection.DeleteOne(context.Background(), bson.M{
-		"_id":  bookmarkObjectID,
-		"user": userObjectID,
-	})
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete bookmark"})
+	err = store.Bookmarks.DeleteOwned(context.Background(), bookmarkObjectID, userObjectID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found or not owned by user"})
 		return
 	}
-
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found or not owned by user"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete bookmark"})
 		return
 	}
 
@@ -0,0 +1,464 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreateClaim(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	itemObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var request struct {
+		Message    string `json:"message" binding:"required"`
+		LostItemID string `json:"lostItemId"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	item, err := store.FoundItems.FindByID(context.Background(), itemObjID)
+	if err != nil || item.Hidden != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	if item.FoundPerson == userObjID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot claim an item you reported"})
+		return
+	}
+	if item.Found {
+		c.JSON(http.StatusConflict, gin.H{"error": "Item has already been returned"})
+		return
+	}
+
+	// The optional lost item must be the claimant's own report
+	var lostItemObjID primitive.ObjectID
+	if request.LostItemID != "" {
+		lostItemObjID, err = primitive.ObjectIDFromHex(request.LostItemID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lost item ID"})
+			return
+		}
+		lostItem, err := store.LostItems.FindByID(context.Background(), lostItemObjID)
+		if err != nil || lostItem.CreatedBy != userObjID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Lost item not found or not owned by user"})
+			return
+		}
+	}
+
+	pending, err := store.Claims.List(context.Background(), store.ClaimFilter{
+		FoundItem: itemObjID,
+		Claimant:  userObjID,
+		Status:    models.ClaimPending,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch claims"})
+		return
+	}
+	if len(pending) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already have a pending claim on this item"})
+		return
+	}
+
+	claim := models.Claim{
+		FoundItem: itemObjID,
+		LostItem:  lostItemObjID,
+		Claimant:  userObjID,
+		Finder:    item.FoundPerson,
+		Message:   request.Message,
+		Status:    models.ClaimPending,
+		Questions: []models.VerificationQuestion{},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := store.Claims.Insert(context.Background(), &claim); err != nil {
+		log.Println("Insert claim error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create claim"})
+		return
+	}
+
+	notifyUser(models.Notification{
+		User:      item.FoundPerson,
+		Type:      models.NotificationClaimCreated,
+		Actor:     userObjID,
+		FoundItem: item.ID,
+		LostItem:  claim.LostItem,
+		Message:   "Someone has claimed your found item \"" + item.Name + "\"",
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Claim submitted successfully",
+		"claim":   claim,
+	})
+}
+
+func GetFoundItemClaims(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	itemObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	item, err := store.FoundItems.FindByID(context.Background(), itemObjID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	if item.FoundPerson != userObjID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the finder can view claims on this item"})
+		return
+	}
+
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	filter := store.ClaimFilter{FoundItem: itemObjID, After: page.After(), Limit: page.Fetch()}
+	result, err := listClaims(page, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch claims"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func GetMyClaims(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	result, err := listClaims(page, store.ClaimFilter{
+		Claimant: userObjID,
+		Status:   models.ClaimStatus(c.Query("status")),
+		After:    page.After(),
+		Limit:    page.Fetch(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch claims"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// listClaims loads a page of claims, newest first.
+func listClaims(page pagination.Page, filter store.ClaimFilter) (pagination.Result[models.Claim], error) {
+	return loadPage(page, claimCursor,
+		func() ([]models.Claim, error) { return store.Claims.List(context.Background(), filter) },
+		func() (int64, error) { return store.Claims.Count(context.Background(), filter) },
+	)
+}
+
+func claimCursor(claim models.Claim) pagination.Cursor {
+	return pagination.After(claim.CreatedAt, claim.ID)
+}
+
+// loadClaim fetches the claim named by the :id param and checks that the
+// authenticated user is its claimant or finder. On failure it writes the
+// response and returns nil.
+func loadClaim(c *gin.Context) (*models.Claim, primitive.ObjectID) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, primitive.NilObjectID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return nil, primitive.NilObjectID
+	}
+
+	claimObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid claim ID"})
+		return nil, primitive.NilObjectID
+	}
+
+	claim, err := store.Claims.FindByID(context.Background(), claimObjID)
+	if err != nil || (claim.Claimant != userObjID && claim.Finder != userObjID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Claim not found"})
+		return nil, primitive.NilObjectID
+	}
+
+	return claim, userObjID
+}
+
+func GetClaimByID(c *gin.Context) {
+	claim, _ := loadClaim(c)
+	if claim == nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+func AddClaimQuestion(c *gin.Context) {
+	claim, userObjID := loadClaim(c)
+	if claim == nil {
+		return
+	}
+	if claim.Finder != userObjID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the finder can ask verification questions"})
+		return
+	}
+
+	var request struct {
+		Question string `json:"question" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil || strings.TrimSpace(request.Question) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Question is required"})
+		return
+	}
+
+	question := models.VerificationQuestion{
+		ID:       primitive.NewObjectID(),
+		Question: strings.TrimSpace(request.Question),
+		AskedAt:  time.Now(),
+	}
+	err := store.Claims.AddQuestion(context.Background(), claim.ID, question)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Claim is no longer pending"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add question"})
+		return
+	}
+
+	notifyUser(claimNotification(claim, claim.Claimant, models.NotificationClaimQuestion, userObjID,
+		"The finder has asked you a question about your claim"))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Question added successfully",
+		"question": question,
+	})
+}
+
+func AnswerClaimQuestion(c *gin.Context) {
+	claim, userObjID := loadClaim(c)
+	if claim == nil {
+		return
+	}
+	if claim.Claimant != userObjID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the claimant can answer verification questions"})
+		return
+	}
+
+	questionObjID, err := primitive.ObjectIDFromHex(c.Param("questionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	var request struct {
+		Answer string `json:"answer" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil || strings.TrimSpace(request.Answer) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Answer is required"})
+		return
+	}
+
+	known := false
+	for _, q := range claim.Questions {
+		if q.ID == questionObjID {
+			known = true
+		}
+	}
+	if !known {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	}
+
+	err = store.Claims.AnswerQuestion(context.Background(), claim.ID, questionObjID, strings.TrimSpace(request.Answer))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Claim is no longer pending"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save answer"})
+		return
+	}
+
+	notifyUser(claimNotification(claim, claim.Finder, models.NotificationClaimAnswered, userObjID,
+		"A claimant has answered your verification question"))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Answer saved successfully"})
+}
+
+func UpdateClaimStatus(c *gin.Context) {
+	claim, userObjID := loadClaim(c)
+	if claim == nil {
+		return
+	}
+
+	var request struct {
+		Status models.ClaimStatus `json:"status" binding:"required"`
+		Note   string             `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	// Finders decide, claimants may only withdraw
+	switch request.Status {
+	case models.ClaimApproved, models.ClaimRejected:
+		if claim.Finder != userObjID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the finder can approve or reject a claim"})
+			return
+		}
+	case models.ClaimWithdrawn:
+		if claim.Claimant != userObjID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the claimant can withdraw a claim"})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be approved, rejected or withdrawn"})
+		return
+	}
+
+	if !claim.Status.CanTransition(request.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Claim is already " + string(claim.Status)})
+		return
+	}
+
+	if request.Status == models.ClaimApproved {
+		if claim.Unanswered() > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "All verification questions must be answered before approval"})
+			return
+		}
+		item, err := store.FoundItems.FindByID(context.Background(), claim.FoundItem)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+			return
+		}
+		if item.Found {
+			c.JSON(http.StatusConflict, gin.H{"error": "Item has already been returned"})
+			return
+		}
+	}
+
+	// The store allows one approved claim per item, so of two approvals
+	// racing for the same item only one gets through
+	err := store.Claims.Decide(context.Background(), claim.ID, request.Status, request.Note)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Another claim on this item has already been approved"})
+		return
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Claim is no longer pending"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update claim"})
+		return
+	}
+
+	switch request.Status {
+	case models.ClaimApproved:
+		notifyUser(claimNotification(claim, claim.Claimant, models.NotificationClaimApproved, userObjID,
+			"Your claim has been approved"))
+		rejectOtherClaims(claim)
+		advanceLostItem(claim.LostItem, models.LostItemMatched, userObjID, "Claim approved by finder")
+	case models.ClaimRejected:
+		notifyUser(claimNotification(claim, claim.Claimant, models.NotificationClaimRejected, userObjID,
+			"Your claim has been rejected"))
+	case models.ClaimWithdrawn:
+		notifyUser(claimNotification(claim, claim.Finder, models.NotificationClaimWithdrawn, userObjID,
+			"A claim on your found item was withdrawn"))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Claim updated successfully",
+		"status":  request.Status,
+	})
+}
+
+// rejectOtherClaims closes the remaining pending claims on an item once one
+// claim has been approved.
+func rejectOtherClaims(approved *models.Claim) {
+	pending, err := store.Claims.List(context.Background(), store.ClaimFilter{
+		FoundItem: approved.FoundItem,
+		Status:    models.ClaimPending,
+	})
+	if err != nil {
+		log.Println("List pending claims error:", err)
+		return
+	}
+	for _, other := range pending {
+		if other.ID == approved.ID {
+			continue
+		}
+		err := store.Claims.Decide(context.Background(), other.ID, models.ClaimRejected, "Another claim was approved")
+		if err == nil {
+			notifyUser(claimNotification(&other, other.Claimant, models.NotificationClaimRejected, approved.Finder,
+				"Your claim has been rejected"))
+		}
+	}
+}
+
+// claimNotification builds a notification to recipient about claim.
+func claimNotification(claim *models.Claim, recipient primitive.ObjectID, kind models.NotificationType, actor primitive.ObjectID, message string) models.Notification {
+	return models.Notification{
+		User:      recipient,
+		Type:      kind,
+		Actor:     actor,
+		FoundItem: claim.FoundItem,
+		LostItem:  claim.LostItem,
+		Message:   message,
+	}
+}
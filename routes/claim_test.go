@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUpdateClaimStatusApprovesOneClaimPerItem(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	finder := primitive.NewObjectID()
+	item := models.FoundItem{Name: "wallet", FoundPerson: finder}
+	if err := store.FoundItems.Insert(ctx, &item); err != nil {
+		t.Fatal(err)
+	}
+	var claims []models.Claim
+	for i := 0; i < 2; i++ {
+		claim := models.Claim{FoundItem: item.ID, Claimant: primitive.NewObjectID(), Finder: finder, Status: models.ClaimPending}
+		if err := store.Claims.Insert(ctx, &claim); err != nil {
+			t.Fatal(err)
+		}
+		claims = append(claims, claim)
+	}
+
+	// Another request approved the first claim after this one checked,
+	// before it got to reject the others
+	if err := store.Claims.Decide(ctx, claims[0].ID, models.ClaimApproved, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	w := call(t, UpdateClaimStatus, http.MethodPut, "/claims/:id/status", "/claims/"+claims[1].ID.Hex()+"/status",
+		finder, map[string]string{"status": string(models.ClaimApproved)})
+	expectStatus(t, w, http.StatusConflict)
+	claim, err := store.Claims.FindByID(ctx, claims[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.Status != models.ClaimPending {
+		t.Errorf("second claim is %s, want it left pending", claim.Status)
+	}
+
+	// Rejecting is not limited
+	w = call(t, UpdateClaimStatus, http.MethodPut, "/claims/:id/status", "/claims/"+claims[1].ID.Hex()+"/status",
+		finder, map[string]string{"status": string(models.ClaimRejected)})
+	expectStatus(t, w, http.StatusOK)
+}
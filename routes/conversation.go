@@ -0,0 +1,362 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxMessageLength = 2000
+)
+
+// CreateConversation opens the thread between the owner of a lost item and
+// the finder of a found item, or returns the existing one. Either of them
+// may start it, optionally with a first message, once the items are tied
+// together: matched, claimed for the lost item, or returned for it.
+func CreateConversation(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		LostItemID  string `json:"lostItemId" binding:"required"`
+		FoundItemID string `json:"foundItemId" binding:"required"`
+		Message     string `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if len(request.Message) > maxMessageLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message is too long"})
+		return
+	}
+
+	lostItemID, err := primitive.ObjectIDFromHex(request.LostItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lost item ID"})
+		return
+	}
+	foundItemID, err := primitive.ObjectIDFromHex(request.FoundItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid found item ID"})
+		return
+	}
+
+	lostItem, err := store.LostItems.FindByID(context.Background(), lostItemID)
+	if err != nil || hiddenFrom(c, lostItem.Hidden, lostItem.CreatedBy) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lost item not found"})
+		return
+	}
+	foundItem, err := store.FoundItems.FindByID(context.Background(), foundItemID)
+	if err != nil || hiddenFrom(c, foundItem.Hidden, foundItem.FoundPerson) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found item not found"})
+		return
+	}
+
+	if userObjID != lostItem.CreatedBy && userObjID != foundItem.FoundPerson {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You must have reported one of the items"})
+		return
+	}
+	if lostItem.CreatedBy == foundItem.FoundPerson {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Both items were reported by the same user"})
+		return
+	}
+	related, err := itemsRelated(lostItemID, foundItem)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create conversation"})
+		return
+	}
+	if !related {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The items have not been matched or claimed"})
+		return
+	}
+
+	now := time.Now()
+	conv := models.Conversation{
+		LostItem:  lostItemID,
+		FoundItem: foundItemID,
+		Owner:     lostItem.CreatedBy,
+		Finder:    foundItem.FoundPerson,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	created, err := store.Conversations.FindOrCreate(context.Background(), &conv)
+	if err != nil {
+		log.Println("FindOrCreate conversation error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create conversation"})
+		return
+	}
+
+	response := gin.H{"conversation": conv}
+	if body := strings.TrimSpace(request.Message); body != "" {
+		message, err := sendMessage(&conv, userObjID, body)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+			return
+		}
+		response["message"] = message
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, response)
+}
+
+// ListConversations returns a page of the user's conversations, newest
+// first, each with its unread message count.
+func ListConversations(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	filter := store.ConversationFilter{User: userObjID, After: page.After(), Limit: page.Fetch()}
+	result, err := loadPage(page, conversationCursor,
+		func() ([]models.Conversation, error) {
+			return store.Conversations.ListForUser(context.Background(), filter)
+		},
+		func() (int64, error) { return store.Conversations.CountForUser(context.Background(), userObjID) },
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversations"})
+		return
+	}
+	for i := range result.Items {
+		if err := countUnread(&result.Items[i], userObjID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversations"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// conversationCursor marks a conversation by when it was started, which
+// unlike its last activity never changes while a client pages through.
+func conversationCursor(conv models.Conversation) pagination.Cursor {
+	return pagination.After(conv.CreatedAt, conv.ID)
+}
+
+// GetUnreadMessageCount returns how many messages are unread across all the
+// user's conversations.
+func GetUnreadMessageCount(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	total, err := store.Conversations.CountUnread(context.Background(), userObjID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"count": total})
+}
+
+func GetConversation(c *gin.Context) {
+	conv, userObjID := loadConversation(c)
+	if conv == nil {
+		return
+	}
+	if err := countUnread(conv, userObjID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, conv)
+}
+
+// ListMessages returns a page of the conversation's messages, newest first.
+func ListMessages(c *gin.Context) {
+	conv, _ := loadConversation(c)
+	if conv == nil {
+		return
+	}
+
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	filter := store.MessageFilter{Conversation: conv.ID, After: page.After(), Limit: page.Fetch()}
+	result, err := loadPage(page, messageCursor,
+		func() ([]models.Message, error) { return store.Messages.List(context.Background(), filter) },
+		func() (int64, error) { return store.Messages.Count(context.Background(), conv.ID) },
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch messages"})
+		return
+	}
+	// Hidden messages keep their place in the thread without their text
+	for i := range result.Items {
+		if result.Items[i].Hidden != nil && !currentRole(c).Staff() {
+			result.Items[i].Body = ""
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func messageCursor(message models.Message) pagination.Cursor {
+	return pagination.After(message.CreatedAt, message.ID)
+}
+
+func SendMessage(c *gin.Context) {
+	conv, userObjID := loadConversation(c)
+	if conv == nil {
+		return
+	}
+
+	var request struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	body := strings.TrimSpace(request.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message cannot be empty"})
+		return
+	}
+	if len(body) > maxMessageLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message is too long"})
+		return
+	}
+
+	message, err := sendMessage(conv, userObjID, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// MarkConversationRead marks every message in the conversation read for the user.
+func MarkConversationRead(c *gin.Context) {
+	conv, userObjID := loadConversation(c)
+	if conv == nil {
+		return
+	}
+
+	err := store.Conversations.MarkRead(context.Background(), conv.ID, userObjID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update conversation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Conversation marked as read"})
+}
+
+// sendMessage stores a message from sender and tells the other participant.
+// The sender has implicitly read everything up to their own message.
+func sendMessage(conv *models.Conversation, sender primitive.ObjectID, body string) (*models.Message, error) {
+	message := models.Message{
+		Conversation: conv.ID,
+		Sender:       sender,
+		Body:         body,
+		CreatedAt:    time.Now(),
+	}
+	if err := store.Messages.Insert(context.Background(), &message); err != nil {
+		log.Println("Insert message error:", err)
+		return nil, err
+	}
+	if err := store.Conversations.Touch(context.Background(), conv.ID, message.CreatedAt); err != nil {
+		log.Println("Touch conversation error:", err)
+	}
+	if err := store.Conversations.MarkRead(context.Background(), conv.ID, sender, message.CreatedAt); err != nil {
+		log.Println("MarkRead conversation error:", err)
+	}
+
+	notifyUser(models.Notification{
+		User:      conv.Other(sender),
+		Type:      models.NotificationMessage,
+		Actor:     sender,
+		LostItem:  conv.LostItem,
+		FoundItem: conv.FoundItem,
+		Message:   "You have a new message",
+	})
+	return &message, nil
+}
+
+// itemsRelated reports whether a conversation about the two items has a
+// reason to exist: the found item was returned for the lost item, the two
+// were matched, or the found item was claimed for the lost item. Without
+// it, anyone could message any finder by naming one of their own items.
+func itemsRelated(lostItemID primitive.ObjectID, foundItem *models.FoundItem) (bool, error) {
+	if foundItem.LostItem == lostItemID {
+		return true, nil
+	}
+	matches, err := store.Matches.Count(context.Background(), store.MatchFilter{LostItem: lostItemID, FoundItem: foundItem.ID})
+	if err != nil || matches > 0 {
+		return matches > 0, err
+	}
+	claims, err := store.Claims.List(context.Background(), store.ClaimFilter{FoundItem: foundItem.ID})
+	if err != nil {
+		return false, err
+	}
+	for _, claim := range claims {
+		if claim.LostItem == lostItemID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// countUnread fills in conv.UnreadCount for userID.
+func countUnread(conv *models.Conversation, userID primitive.ObjectID) error {
+	count, err := store.Messages.CountUnread(context.Background(), conv.ID, userID, conv.ReadAt(userID))
+	if err != nil {
+		return err
+	}
+	conv.UnreadCount = count
+	return nil
+}
+
+// loadConversation fetches the conversation named by the :id param and
+// checks that the authenticated user takes part in it. On failure it writes
+// the response and returns nil.
+func loadConversation(c *gin.Context) (*models.Conversation, primitive.ObjectID) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return nil, primitive.NilObjectID
+	}
+
+	convObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return nil, primitive.NilObjectID
+	}
+
+	conv, err := store.Conversations.FindByID(context.Background(), convObjID)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && !conv.HasParticipant(userObjID)) {
+		// Don't reveal that other people's conversations exist
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conversation not found"})
+		return nil, primitive.NilObjectID
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversation"})
+		return nil, primitive.NilObjectID
+	}
+	return conv, userObjID
+}
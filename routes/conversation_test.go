@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateConversationNeedsRelatedItems(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	owner, finder := primitive.NewObjectID(), primitive.NewObjectID()
+	lost := models.LostItem{Name: "wallet", CreatedBy: owner}
+	if err := store.LostItems.Insert(ctx, &lost); err != nil {
+		t.Fatal(err)
+	}
+	newFoundItem := func(lostItem primitive.ObjectID) models.FoundItem {
+		item := models.FoundItem{Name: "wallet", FoundPerson: finder, LostItem: lostItem}
+		if err := store.FoundItems.Insert(ctx, &item); err != nil {
+			t.Fatal(err)
+		}
+		return item
+	}
+
+	unrelated := newFoundItem(primitive.NilObjectID)
+	matched := newFoundItem(primitive.NilObjectID)
+	if err := store.Matches.Upsert(ctx, &models.Match{LostItem: lost.ID, FoundItem: matched.ID, Score: 0.8}); err != nil {
+		t.Fatal(err)
+	}
+	claimed := newFoundItem(primitive.NilObjectID)
+	claim := models.Claim{FoundItem: claimed.ID, LostItem: lost.ID, Claimant: owner, Finder: finder, Status: models.ClaimPending}
+	if err := store.Claims.Insert(ctx, &claim); err != nil {
+		t.Fatal(err)
+	}
+	returned := newFoundItem(lost.ID)
+
+	for name, tt := range map[string]struct {
+		foundItem primitive.ObjectID
+		user      primitive.ObjectID
+		want      int
+	}{
+		"unrelated items":                {unrelated.ID, owner, http.StatusForbidden},
+		"unrelated items, by the finder": {unrelated.ID, finder, http.StatusForbidden},
+		"matched":                        {matched.ID, owner, http.StatusCreated},
+		"claimed":                        {claimed.ID, finder, http.StatusCreated},
+		"returned":                       {returned.ID, owner, http.StatusCreated},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := call(t, CreateConversation, http.MethodPost, "/conversations", "/conversations", tt.user,
+				map[string]string{"lostItemId": lost.ID.Hex(), "foundItemId": tt.foundItem.Hex()})
+			expectStatus(t, w, tt.want)
+		})
+	}
+}
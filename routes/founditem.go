@@ -2,17 +2,18 @@ package routes
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
-	"lostfound-backend/db"
 	"lostfound-backend/models"
-	"lostfound-backend/utils"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func AddFoundItem(c *gin.Context) {
@@ -28,96 +29,178 @@ func AddFoundItem(c *gin.Context) {
 		return
 	}
 
-	file, err := c.FormFile("image")
+	// Images are optional; several can be sent as "images"
+	files, captions, err := formImages(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Image is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid images", "details": err.Error()})
 		return
 	}
 
-	// Upload to Cloudinary
-	imageURL, err := utils.UploadImage(file)
-	if err != nil || imageURL == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	// Only the fields a finder describes are read. The returned flag
+	// changes through an approved claim and moderation through staff, so
+	// neither can be set here
+	var request struct {
+		Name             string             `json:"name" form:"name"`
+		Description      string             `json:"description" form:"description"`
+		Category         string             `json:"category" form:"category"`
+		District         string             `json:"district" form:"district"`
+		State            string             `json:"state" form:"state"`
+		LocationFound    string             `json:"locationFound" form:"locationFound"`
+		FoundPersonPhone string             `json:"foundPersonPhone" form:"foundPersonPhone"`
+		LostItem         primitive.ObjectID `json:"lostItem" form:"-"`
+	}
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
+	foundItem := models.FoundItem{
+		Name:             request.Name,
+		Description:      request.Description,
+		Category:         request.Category,
+		District:         request.District,
+		State:            request.State,
+		LocationFound:    request.LocationFound,
+		FoundPersonPhone: request.FoundPersonPhone,
+	}
 
-	var foundItem models.FoundItem
-	if err := c.ShouldBind(&foundItem); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	position, err := parsePosition(c.PostForm("latitude"), c.PostForm("longitude"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The finder may link the lost report the item answers; its owner is
+	// then the lost person, whatever the client sent
+	lostItemID := request.LostItem
+	if value := c.PostForm("lostItem"); value != "" {
+		if lostItemID, err = primitive.ObjectIDFromHex(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lost item ID"})
+			return
+		}
+	}
+	if !lostItemID.IsZero() {
+		lostItem, err := store.LostItems.FindByID(context.Background(), lostItemID)
+		if err != nil || hiddenFrom(c, lostItem.Hidden, lostItem.CreatedBy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Lost item not found"})
+			return
+		}
+		foundItem.LostItem = lostItem.ID
+		foundItem.LostPerson = lostItem.CreatedBy
+	}
+
+	// Upload to the image store once the rest of the form is valid
+	images, err := uploadImages(files, captions)
+	if err != nil {
+		imageUploadError(c, err)
 		return
 	}
 
 	// Set additional fields
-	foundItem.Image = imageURL
+	foundItem.Position = position
+	foundItem.SetImages(images)
 	foundItem.DateFound = time.Now()
+	foundItem.CreatedAt = foundItem.DateFound
+	foundItem.UpdatedAt = foundItem.DateFound
 	foundItem.FoundPerson = objID
 
-	collection := db.GetCollection("founditems")
-	result, err := collection.InsertOne(context.Background(), foundItem)
-	if err != nil {
+	if err := store.FoundItems.Insert(context.Background(), &foundItem); err != nil {
+		deleteImages(images)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create found item"})
 		return
 	}
 
 	// Add notification to the lost person
 	if foundItem.LostPerson != primitive.NilObjectID {
-		notification := models.Notification{
+		notifyUser(models.Notification{
+			User:      foundItem.LostPerson,
+			Type:      models.NotificationItemFound,
+			Actor:     foundItem.FoundPerson,
+			LostItem:  foundItem.LostItem,
+			FoundItem: foundItem.ID,
 			Message:   "Your lost item has been reported as found",
-			Read:      false,
-			CreatedAt: time.Now(),
-		}
-
-		userCollection := db.GetCollection("users")
-		_, err = userCollection.UpdateOne(
-			context.Background(),
-			bson.M{"_id": foundItem.LostPerson},
-			bson.M{"$push": bson.M{"notifications": notification}},
-		)
+		})
 	}
 
+	matchFoundItem(&foundItem)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Found item reported successfully",
-		"itemId":  result.InsertedID,
+		"itemId":  foundItem.ID,
 	})
 }
 
 func GetAllFoundItems(c *gin.Context) {
-	collection := db.GetCollection("founditems")
-	filter := bson.M{}
-
-	// Add pagination options
-	findOptions := options.Find()
-	if limit := c.Query("limit"); limit != "" {
-		limitInt, err := utils.StringToInt(limit)
-		if err == nil {
-			findOptions.SetLimit(int64(limitInt))
-		}
+	query, err := parseListQuery(c, "dateFound", store.FoundItemSortFields())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if skip := c.Query("skip"); skip != "" {
-		skipInt, err := utils.StringToInt(skip)
-		if err == nil {
-			findOptions.SetSkip(int64(skipInt))
-		}
+	filter := store.FoundItemFilter{
+		Sort:        query.Sort,
+		FoundAfter:  query.From,
+		FoundBefore: query.To,
+		State:       query.State,
+		Categories:  query.Categories,
+		HasImage:    query.HasImage,
+		FoundPerson: query.Owner,
+	}
+	if !query.Owner.IsZero() && canSeeHidden(c, query.Owner) {
+		filter.Visibility = store.AllItems
+	}
+	switch found := c.Query("found"); found {
+	case "":
+	case "true", "false":
+		value := found == "true"
+		filter.Found = &value
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "found must be true or false"})
+		return
 	}
 
-	cursor, err := collection.Find(context.Background(), filter, findOptions)
+	near, err := parseNear(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer cursor.Close(context.Background())
+	filter.Near = near
+	text, err := parseTextQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if text != nil && near != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errTextWithNear.Error()})
+		return
+	}
+	filter.Text = text
+	if (near != nil || text != nil) && query.Sort != (store.SortOrder{}) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errSortWithSearch.Error()})
+		return
+	}
+
+	// Searches and sorted listings page by offset; the default newest-first
+	// order pages by keyset
+	keyset := near == nil && text == nil && query.Sort.Default()
+	page, ok := parsePage(c, keyset)
+	if !ok {
+		return
+	}
+	var key func(models.FoundItem) pagination.Cursor
+	if keyset {
+		key = foundItemCursor
+	}
+	filter.After, filter.Skip, filter.Limit = page.After(), page.Skip(), page.Fetch()
 
-	var items []models.FoundItem
-	if err := cursor.All(context.Background(), &items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Decode error"})
+	result, err := listFoundItems(page, key, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
 
 	// Populate user information
-	userCollection := db.GetCollection("users")
+	items := result.Items
 	for i, item := range items {
-		var foundUser models.User
-		err := userCollection.FindOne(context.Background(), bson.M{"_id": item.FoundBy}).Decode(&foundUser)
+		foundUser, err := store.Users.FindByID(context.Background(), item.FoundBy())
 		if err == nil {
 			items[i].FoundByUser = &models.User{
 				Username: foundUser.Username,
@@ -126,8 +209,7 @@ func GetAllFoundItems(c *gin.Context) {
 		}
 
 		if item.LostPerson != primitive.NilObjectID {
-			var lostUser models.User
-			err := userCollection.FindOne(context.Background(), bson.M{"_id": item.LostPerson}).Decode(&lostUser)
+			lostUser, err := store.Users.FindByID(context.Background(), item.LostPerson)
 			if err == nil {
 				items[i].LostPersonUser = &models.User{
 					Username: lostUser.Username,
@@ -137,7 +219,53 @@ func GetAllFoundItems(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, items)
+	shareContact(c, result.Items)
+	c.JSON(http.StatusOK, result)
+}
+
+// listFoundItems loads a page of found items matching filter.
+func listFoundItems(page pagination.Page, key func(models.FoundItem) pagination.Cursor, filter store.FoundItemFilter) (pagination.Result[models.FoundItem], error) {
+	return loadPage(page, key,
+		func() ([]models.FoundItem, error) { return store.FoundItems.List(context.Background(), filter) },
+		func() (int64, error) { return store.FoundItems.Count(context.Background(), filter) },
+	)
+}
+
+// shareContact fills in the finder's phone on the items the current user
+// may contact the finder about: their own, and those they have an approved
+// claim on.
+func shareContact(c *gin.Context, items []models.FoundItem) {
+	userID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		return
+	}
+	var approved map[primitive.ObjectID]bool
+	for i := range items {
+		item := &items[i]
+		if item.FoundPersonPhone == "" {
+			continue
+		}
+		if item.FoundPerson != userID {
+			// Load the user's approved claims once, when first needed
+			if approved == nil {
+				approved = map[primitive.ObjectID]bool{}
+				claims, err := store.Claims.List(context.Background(), store.ClaimFilter{
+					Claimant: userID,
+					Status:   models.ClaimApproved,
+				})
+				if err != nil {
+					log.Println("List claims error:", err)
+				}
+				for _, claim := range claims {
+					approved[claim.FoundItem] = true
+				}
+			}
+			if !approved[item.ID] {
+				continue
+			}
+		}
+		item.ContactPhone = item.FoundPersonPhone
+	}
 }
 
 func GetFoundItemByID(c *gin.Context) {
@@ -148,19 +276,15 @@ func GetFoundItemByID(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("founditems")
-	var item models.FoundItem
-	err = collection.FindOne(context.Background(), bson.M{"_id": objID}).Decode(&item)
-	if err != nil {
+	item, err := store.FoundItems.FindByID(context.Background(), objID)
+	if err != nil || hiddenFrom(c, item.Hidden, item.FoundPerson) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
 		return
 	}
 
 	// Populate user information
-	userCollection := db.GetCollection("users")
 	if item.FoundBy() != primitive.NilObjectID {
-		var foundUser models.User
-		err := userCollection.FindOne(context.Background(), bson.M{"_id": item.FoundBy}).Decode(&foundUser)
+		foundUser, err := store.Users.FindByID(context.Background(), item.FoundBy())
 		if err == nil {
 			item.FoundByUser = &models.User{
 				Username: foundUser.Username,
@@ -170,8 +294,7 @@ func GetFoundItemByID(c *gin.Context) {
 	}
 
 	if item.LostPerson != primitive.NilObjectID {
-		var lostUser models.User
-		err := userCollection.FindOne(context.Background(), bson.M{"_id": item.LostPerson}).Decode(&lostUser)
+		lostUser, err := store.Users.FindByID(context.Background(), item.LostPerson)
 		if err == nil {
 			item.LostPersonUser = &models.User{
 				Username: lostUser.Username,
@@ -180,7 +303,18 @@ func GetFoundItemByID(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, item)
+	// Populate the lost item the found item answers
+	if item.LostItem != primitive.NilObjectID {
+		lostItem, err := store.LostItems.FindByID(context.Background(), item.LostItem)
+		if err == nil && !hiddenFrom(c, lostItem.Hidden, lostItem.CreatedBy) {
+			item.LostItemDetails = lostItem
+		}
+	}
+
+	// Only the finder and an approved claimant see the phone
+	shared := []models.FoundItem{*item}
+	shareContact(c, shared)
+	c.JSON(http.StatusOK, shared[0])
 }
 
 func GetFoundItemsByLostItem(c *gin.Context) {
@@ -191,25 +325,29 @@ func GetFoundItemsByLostItem(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("founditems")
-	cursor, err := collection.Find(context.Background(), bson.M{"lostPerson": objID})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	if lostItem, err := store.LostItems.FindByID(context.Background(), objID); err != nil || hiddenFrom(c, lostItem.Hidden, lostItem.CreatedBy) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lost item not found"})
 		return
 	}
-	defer cursor.Close(context.Background())
 
-	var items []models.FoundItem
-	if err := cursor.All(context.Background(), &items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Decode error"})
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	result, err := listFoundItems(page, foundItemCursor, store.FoundItemFilter{
+		LostItem: objID,
+		After:    page.After(),
+		Limit:    page.Fetch(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
 
 	// Populate found by user information
-	userCollection := db.GetCollection("users")
+	items := result.Items
 	for i, item := range items {
-		var user models.User
-		err := userCollection.FindOne(context.Background(), bson.M{"_id": item.FoundBy}).Decode(&user)
+		user, err := store.Users.FindByID(context.Background(), item.FoundBy())
 		if err == nil {
 			items[i].FoundByUser = &models.User{
 				Username: user.Username,
@@ -218,7 +356,8 @@ func GetFoundItemsByLostItem(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, items)
+	shareContact(c, result.Items)
+	c.JSON(http.StatusOK, result)
 }
 
 func GetFoundItemsByUser(c *gin.Context) {
@@ -229,26 +368,26 @@ func GetFoundItemsByUser(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("founditems")
-	cursor, err := collection.Find(context.Background(), bson.M{"foundBy": objID})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	page, ok := parsePage(c, true)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.Background())
 
-	var items []models.FoundItem
-	if err := cursor.All(context.Background(), &items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Decode error"})
+	filter := store.FoundItemFilter{FoundPerson: objID, After: page.After(), Limit: page.Fetch()}
+	if canSeeHidden(c, objID) {
+		filter.Visibility = store.AllItems
+	}
+	result, err := listFoundItems(page, foundItemCursor, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
 
 	// Populate lost person information
-	userCollection := db.GetCollection("users")
+	items := result.Items
 	for i, item := range items {
 		if item.LostPerson != primitive.NilObjectID {
-			var user models.User
-			err := userCollection.FindOne(context.Background(), bson.M{"_id": item.LostPerson}).Decode(&user)
+			user, err := store.Users.FindByID(context.Background(), item.LostPerson)
 			if err == nil {
 				items[i].LostPersonUser = &models.User{
 					Username: user.Username,
@@ -258,7 +397,8 @@ func GetFoundItemsByUser(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, items)
+	shareContact(c, result.Items)
+	c.JSON(http.StatusOK, result)
 }
 
 func UpdateFoundItem(c *gin.Context) {
@@ -282,28 +422,66 @@ func UpdateFoundItem(c *gin.Context) {
 		return
 	}
 
-	var updateData models.FoundItem
-	if err := c.ShouldBindJSON(&updateData); err != nil {
+	// Only fields present in the request are changed. Images are managed
+	// through the image endpoints, and the returned flag and lost item
+	// link change only through claims
+	var request struct {
+		Name             *string  `json:"name"`
+		Description      *string  `json:"description"`
+		Category         *string  `json:"category"`
+		District         *string  `json:"district"`
+		State            *string  `json:"state"`
+		LocationFound    *string  `json:"locationFound"`
+		FoundPersonPhone *string  `json:"foundPersonPhone"`
+		Latitude         *float64 `json:"latitude"`
+		Longitude        *float64 `json:"longitude"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
-
-	collection := db.GetCollection("founditems")
-	result, err := collection.UpdateOne(
-		context.Background(),
-		bson.M{"_id": objID, "foundBy": userObjID},
-		bson.M{"$set": updateData},
-	)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+	update := store.FoundItemUpdate{
+		Name:             request.Name,
+		Description:      request.Description,
+		Category:         request.Category,
+		District:         request.District,
+		State:            request.State,
+		LocationFound:    request.LocationFound,
+		FoundPersonPhone: request.FoundPersonPhone,
+	}
+	if request.Name != nil && strings.TrimSpace(*request.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The name field cannot be empty"})
+		return
+	}
+	if request.Latitude != nil || request.Longitude != nil {
+		if request.Latitude == nil || request.Longitude == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "latitude and longitude must be updated together"})
+			return
+		}
+		position, err := models.NewGeoPoint(*request.Latitude, *request.Longitude)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		update.Position = position
+	}
+	if update.Empty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
 		return
 	}
 
-	if result.MatchedCount == 0 {
+	item, err := store.FoundItems.UpdateOwned(context.Background(), objID, userObjID, update)
+	if errors.Is(err, store.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+
+	// Re-score the edited item against open lost items
+	matchFoundItem(item)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Item updated successfully",
@@ -319,6 +497,19 @@ func UpdateFoundStatus(c *gin.Context) {
 		return
 	}
 
+	// Get the authenticated user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
 	var request struct {
 		Found bool `json:"found"`
 	}
@@ -327,22 +518,57 @@ func UpdateFoundStatus(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("founditems")
-	result, err := collection.UpdateOne(
-		context.Background(),
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"found": request.Found}},
-	)
-
+	item, err := store.FoundItems.FindByID(context.Background(), objID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	if item.FoundPerson != userObjID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the finder can update the status"})
 		return
 	}
 
-	if result.MatchedCount == 0 {
+	// Only an approved claim can mark the item as returned
+	var owner, lostItemID primitive.ObjectID
+	if request.Found {
+		claims, err := store.Claims.List(context.Background(), store.ClaimFilter{
+			FoundItem: objID,
+			Status:    models.ClaimApproved,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch claims"})
+			return
+		}
+		if len(claims) == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "An approved claim is required to mark the item as returned"})
+			return
+		}
+		owner = claims[0].Claimant
+		lostItemID = claims[0].LostItem
+	}
+
+	err = store.FoundItems.SetFound(context.Background(), objID, request.Found, lostItemID, owner)
+	if errors.Is(err, store.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+		return
+	}
+
+	if request.Found {
+		advanceLostItem(lostItemID, models.LostItemRecovered, userObjID, "Returned by finder")
+		notifyUser(models.Notification{
+			User:      owner,
+			Type:      models.NotificationItemReturned,
+			Actor:     userObjID,
+			LostItem:  lostItemID,
+			FoundItem: item.ID,
+			Message:   "Your item \"" + item.Name + "\" has been marked as returned",
+		})
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Status updated successfully",
@@ -376,35 +602,21 @@ func DeleteFoundItem(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("founditems")
-
-	// First verify the item exists and belongs to the user
-	var item models.FoundItem
-	err = collection.FindOne(context.Background(), bson.M{
-		"_id":     objID,
-		"foundBy": userObjID,
-	}).Decode(&item)
-
-	if err != nil {
+	// Delete the item only if it belongs to the user
+	item, err := store.FoundItems.DeleteOwned(context.Background(), objID, userObjID)
+	if errors.Is(err, store.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
 		return
 	}
-
-	// Delete the item
-	result, err := collection.DeleteOne(context.Background(), bson.M{
-		"_id":     objID,
-		"foundBy": userObjID,
-	})
-
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
 		return
 	}
 
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
-		return
+	if err := store.Matches.DeleteByFoundItem(context.Background(), objID); err != nil {
+		log.Println("DeleteByFoundItem error:", err)
 	}
+	deleteImages(item.ImageList())
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Item deleted successfully",
@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"lostfound-backend/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAddFoundItemIgnoresProtectedFields(t *testing.T) {
+	useMemory(t)
+	finder := primitive.NewObjectID()
+
+	requests := map[string]func() interface{}{
+		"json": func() interface{} {
+			return map[string]interface{}{
+				"name":        "Wallet",
+				"description": "black",
+				"found":       true,
+				"hidden":      map[string]interface{}{"by": primitive.NewObjectID().Hex(), "reason": "forged"},
+				"foundPerson": primitive.NewObjectID().Hex(),
+			}
+		},
+		"form": func() interface{} {
+			var form bytes.Buffer
+			mw := multipart.NewWriter(&form)
+			for _, field := range [][2]string{
+				{"name", "Wallet"}, {"description", "black"},
+				{"found", "true"}, {"Found", "true"}, {"hidden", "true"}, {"Hidden", "true"},
+			} {
+				mw.WriteField(field[0], field[1])
+			}
+			mw.Close()
+			return formBody{&form, mw.FormDataContentType()}
+		},
+	}
+	for name, body := range requests {
+		t.Run(name, func(t *testing.T) {
+			w := call(t, AddFoundItem, http.MethodPost, "/founditems", "/founditems", finder, body())
+			expectStatus(t, w, http.StatusCreated)
+			var response struct {
+				ItemID primitive.ObjectID `json:"itemId"`
+			}
+			decode(t, w, &response)
+
+			item, err := store.FoundItems.FindByID(context.Background(), response.ItemID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if item.Found {
+				t.Error("item was created already returned")
+			}
+			if item.Hidden != nil {
+				t.Errorf("item was created hidden: %+v", item.Hidden)
+			}
+			if item.FoundPerson != finder {
+				t.Errorf("item was created for %s, not the caller", item.FoundPerson.Hex())
+			}
+			if item.Name != "Wallet" || item.Description != "black" {
+				t.Errorf("item is %q, %q; want the submitted name and description", item.Name, item.Description)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultNearRadius = 5000   // meters
+	maxNearRadius     = 100000 // meters
+)
+
+// parseNear reads the near=lat,lng and radius=meters query parameters. It
+// returns nil when no near parameter was given.
+func parseNear(c *gin.Context) (*store.GeoNear, error) {
+	near := c.Query("near")
+	if near == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(near, ",")
+	if len(parts) != 2 {
+		return nil, errors.New("near must be lat,lng")
+	}
+	point, err := parsePosition(parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	radius := float64(defaultNearRadius)
+	if r := c.Query("radius"); r != "" {
+		radius, err = strconv.ParseFloat(r, 64)
+		if err != nil || radius <= 0 || radius > maxNearRadius {
+			return nil, errors.New("radius must be a distance in meters between 0 and 100000")
+		}
+	}
+
+	return &store.GeoNear{Point: point, Radius: radius}, nil
+}
+
+// parsePosition turns latitude and longitude strings into a GeoJSON point.
+// Both empty means no position was supplied and returns nil.
+func parsePosition(lat, lng string) (*models.GeoPoint, error) {
+	lat, lng = strings.TrimSpace(lat), strings.TrimSpace(lng)
+	if lat == "" && lng == "" {
+		return nil, nil
+	}
+	latF, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return nil, errors.New("invalid latitude")
+	}
+	lngF, err := strconv.ParseFloat(lng, 64)
+	if err != nil {
+		return nil, errors.New("invalid longitude")
+	}
+	return models.NewGeoPoint(latF, lngF)
+}
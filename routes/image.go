@@ -0,0 +1,475 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"lostfound-backend/imageproc"
+	"lostfound-backend/imagestore"
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxCaptionLength caps image captions, in bytes.
+const maxCaptionLength = 300
+
+var errTooManyImages = errors.New("a report can have at most " + strconv.Itoa(models.MaxImages) + " images")
+
+// uploadImage checks an uploaded image, strips its metadata and saves it
+// with its medium and thumbnail variants in the configured image store.
+func uploadImage(fileHeader *multipart.FileHeader) (*models.Image, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	processed, err := imageproc.Process(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// The variants share the original's format, so they share its extension
+	key := imagestore.NewKey(processed.Original.Ext())
+	variants := []struct {
+		key     string
+		variant imageproc.Variant
+	}{
+		{key, processed.Original},
+		{imagestore.VariantKey(key, "medium"), processed.Medium},
+		{imagestore.VariantKey(key, "thumb"), processed.Thumbnail},
+	}
+	ctx := context.Background()
+	for i, v := range variants {
+		err := imagestore.Put(ctx, v.key, bytes.NewReader(v.variant.Data), v.variant.ContentType)
+		if err != nil {
+			// Don't leave a partial set behind
+			for _, stored := range variants[:i] {
+				if err := imagestore.Delete(ctx, stored.key); err != nil {
+					log.Println("Delete image error:", err)
+				}
+			}
+			return nil, fmt.Errorf("failed to upload image: %v", err)
+		}
+	}
+
+	return &models.Image{
+		ID:        primitive.NewObjectID(),
+		Key:       key,
+		URL:       imagestore.URL(variants[0].key),
+		Medium:    imagestore.URL(variants[1].key),
+		Thumbnail: imagestore.URL(variants[2].key),
+		Hash:      processed.Hash.String(),
+	}, nil
+}
+
+// uploadImages uploads files in order, giving each the caption at the same
+// index. If one upload fails, the images already stored are deleted again.
+func uploadImages(files []*multipart.FileHeader, captions []string) ([]models.Image, error) {
+	images := make([]models.Image, 0, len(files))
+	for i, file := range files {
+		image, err := uploadImage(file)
+		if err != nil {
+			for _, uploaded := range images {
+				deleteStoredImage(uploaded)
+			}
+			return nil, err
+		}
+		if i < len(captions) {
+			image.Caption = captions[i]
+		}
+		images = append(images, *image)
+	}
+	return images, nil
+}
+
+// deleteImages removes images that are no longer used from the image store
+// in the background.
+func deleteImages(images []models.Image) {
+	if len(images) == 0 {
+		return
+	}
+	go func() {
+		for _, image := range images {
+			deleteStoredImage(image)
+		}
+	}()
+}
+
+// deleteStoredImage removes an image and its variants from the image store.
+// Failures are only logged; the sweeper retries later.
+func deleteStoredImage(image models.Image) {
+	key := image.Key
+	if key == "" {
+		// Images uploaded before keys were recorded only have their URL,
+		// and some point outside the store altogether
+		var ok bool
+		if key, ok = imagestore.Key(image.URL); !ok {
+			return
+		}
+	}
+	ctx := context.Background()
+	for _, key := range []string{key, imagestore.VariantKey(key, "medium"), imagestore.VariantKey(key, "thumb")} {
+		if err := imagestore.Delete(ctx, key); err != nil {
+			log.Println("Delete image error:", err)
+		}
+	}
+}
+
+// formImages returns the files of a multipart request's "images" fields,
+// plus the older single "image" field, and their "captions". Requests that
+// are not multipart have no images.
+func formImages(c *gin.Context) ([]*multipart.FileHeader, []string, error) {
+	form, err := c.MultipartForm()
+	if errors.Is(err, http.ErrNotMultipart) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	files := append(append([]*multipart.FileHeader(nil), form.File["image"]...), form.File["images"]...)
+	if len(files) > models.MaxImages {
+		return nil, nil, errTooManyImages
+	}
+	captions := form.Value["captions"]
+	for i := range captions {
+		captions[i] = strings.TrimSpace(captions[i])
+		if len(captions[i]) > maxCaptionLength {
+			return nil, nil, fmt.Errorf("captions can be at most %d characters", maxCaptionLength)
+		}
+	}
+	return files, captions, nil
+}
+
+// imageUploadError responds to a failed uploadImage: a 4xx naming the
+// problem when the file was rejected, a 500 otherwise.
+func imageUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, imageproc.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, imageproc.ErrUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, imageproc.ErrDimensions), errors.Is(err, imageproc.ErrCorrupt):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Println("UploadImage error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	}
+}
+
+// itemImages reads and writes the images of one kind of report, so lost and
+// found items can share the image endpoints.
+type itemImages struct {
+	// load returns who owns the report, its images and their version
+	load func(ctx context.Context, id primitive.ObjectID) (primitive.ObjectID, []models.Image, int, error)
+	// save replaces the images of a report owned by owner if they are still
+	// at version, and returns them
+	save func(ctx context.Context, id, owner primitive.ObjectID, version int, images []models.Image) ([]models.Image, error)
+}
+
+var lostItemImages = itemImages{
+	load: func(ctx context.Context, id primitive.ObjectID) (primitive.ObjectID, []models.Image, int, error) {
+		item, err := store.LostItems.FindByID(ctx, id)
+		if err != nil {
+			return primitive.NilObjectID, nil, 0, err
+		}
+		return item.CreatedBy, item.ImageList(), item.ImagesVersion, nil
+	},
+	save: func(ctx context.Context, id, owner primitive.ObjectID, version int, images []models.Image) ([]models.Image, error) {
+		item, err := store.LostItems.UpdateOwned(ctx, id, owner, store.LostItemUpdate{Images: &images, ImagesVersion: version})
+		if err != nil {
+			return nil, err
+		}
+		return item.Images, nil
+	},
+}
+
+var foundItemImages = itemImages{
+	load: func(ctx context.Context, id primitive.ObjectID) (primitive.ObjectID, []models.Image, int, error) {
+		item, err := store.FoundItems.FindByID(ctx, id)
+		if err != nil {
+			return primitive.NilObjectID, nil, 0, err
+		}
+		return item.FoundPerson, item.ImageList(), item.ImagesVersion, nil
+	},
+	save: func(ctx context.Context, id, owner primitive.ObjectID, version int, images []models.Image) ([]models.Image, error) {
+		item, err := store.FoundItems.SetImages(ctx, id, owner, version, images)
+		if err != nil {
+			return nil, err
+		}
+		return item.Images, nil
+	},
+}
+
+// maxImageRetries is how many times an image edit is retried when another
+// request changed the report's images in the meantime.
+const maxImageRetries = 5
+
+// imageRequestError rejects an image edit with status.
+type imageRequestError struct {
+	status  int
+	message string
+}
+
+func (e *imageRequestError) Error() string { return e.message }
+
+// owned resolves the :id of an image endpoint to a report the caller owns.
+// It writes the error response and returns false otherwise.
+func (kind itemImages) owned(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID format"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	owner, _, _, err := kind.load(context.Background(), id)
+	if err != nil || owner != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return id, userID, true
+}
+
+// modify applies change to the current images of a report owned by userID
+// and saves the result with a version check, starting over from a fresh
+// read when another request changed the images in between. change may run
+// several times, so it must not have side effects beyond its result. The
+// saved images are sent with status; on failure modify writes the error
+// response and returns false.
+func (kind itemImages) modify(c *gin.Context, id, userID primitive.ObjectID, status int, change func([]models.Image) ([]models.Image, error)) bool {
+	var saved []models.Image
+	var err error
+	for attempt := 0; ; attempt++ {
+		var owner primitive.ObjectID
+		var images []models.Image
+		var version int
+		owner, images, version, err = kind.load(context.Background(), id)
+		if err == nil && owner != userID {
+			err = store.ErrNotFound
+		}
+		if err != nil {
+			break
+		}
+		if images, err = change(images); err != nil {
+			break
+		}
+		saved, err = kind.save(context.Background(), id, userID, version, images)
+		if !errors.Is(err, store.ErrConflict) || attempt == maxImageRetries {
+			break
+		}
+	}
+
+	var requestErr *imageRequestError
+	switch {
+	case err == nil:
+		if saved == nil {
+			saved = []models.Image{}
+		}
+		c.JSON(status, gin.H{"images": saved})
+		return true
+	case errors.As(err, &requestErr):
+		c.JSON(requestErr.status, gin.H{"error": requestErr.message})
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
+	case errors.Is(err, store.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "The item's images are being changed by another request, try again"})
+	default:
+		log.Println("Save images error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update images"})
+	}
+	return false
+}
+
+// imageID reads the :imageId parameter. It writes the error response and
+// returns false when it is malformed.
+func imageID(c *gin.Context) (primitive.ObjectID, bool) {
+	imageID, err := primitive.ObjectIDFromHex(c.Param("imageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image ID format"})
+		return primitive.NilObjectID, false
+	}
+	return imageID, true
+}
+
+// imageIndex finds the image with imageID.
+func imageIndex(images []models.Image, imageID primitive.ObjectID) (int, error) {
+	for i := range images {
+		if images[i].ID == imageID {
+			return i, nil
+		}
+	}
+	return 0, &imageRequestError{http.StatusNotFound, "Image not found"}
+}
+
+// add appends the uploaded "images" (with optional "captions") to a report.
+func (kind itemImages) add(c *gin.Context) {
+	id, userID, ok := kind.owned(c)
+	if !ok {
+		return
+	}
+
+	files, captions, err := formImages(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid images", "details": err.Error()})
+		return
+	}
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one image is required"})
+		return
+	}
+
+	uploaded, err := uploadImages(files, captions)
+	if err != nil {
+		imageUploadError(c, err)
+		return
+	}
+	ok = kind.modify(c, id, userID, http.StatusCreated, func(images []models.Image) ([]models.Image, error) {
+		if len(images)+len(uploaded) > models.MaxImages {
+			return nil, &imageRequestError{http.StatusBadRequest, errTooManyImages.Error()}
+		}
+		return append(images, uploaded...), nil
+	})
+	if !ok {
+		deleteImages(uploaded)
+	}
+}
+
+// remove takes an image off a report. If it was the primary image, the
+// next one becomes primary.
+func (kind itemImages) remove(c *gin.Context) {
+	id, userID, ok := kind.owned(c)
+	if !ok {
+		return
+	}
+	imageID, ok := imageID(c)
+	if !ok {
+		return
+	}
+
+	var removed models.Image
+	ok = kind.modify(c, id, userID, http.StatusOK, func(images []models.Image) ([]models.Image, error) {
+		i, err := imageIndex(images, imageID)
+		if err != nil {
+			return nil, err
+		}
+		removed = images[i]
+		return append(images[:i], images[i+1:]...), nil
+	})
+	if ok {
+		deleteImages([]models.Image{removed})
+	}
+}
+
+// reorder puts a report's images in the order given by {"order": [ids]},
+// which must list each image exactly once.
+func (kind itemImages) reorder(c *gin.Context) {
+	id, userID, ok := kind.owned(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Order []primitive.ObjectID `json:"order" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	errOrder := &imageRequestError{http.StatusBadRequest, "order must list every image of the item exactly once"}
+	kind.modify(c, id, userID, http.StatusOK, func(images []models.Image) ([]models.Image, error) {
+		byID := make(map[primitive.ObjectID]models.Image, len(images))
+		for _, image := range images {
+			byID[image.ID] = image
+		}
+		ordered := make([]models.Image, 0, len(images))
+		for _, imageID := range request.Order {
+			image, ok := byID[imageID]
+			if !ok {
+				return nil, errOrder
+			}
+			delete(byID, imageID)
+			ordered = append(ordered, image)
+		}
+		if len(ordered) != len(images) {
+			return nil, errOrder
+		}
+		return ordered, nil
+	})
+}
+
+// update changes an image's caption and can make it the primary image.
+func (kind itemImages) update(c *gin.Context) {
+	id, userID, ok := kind.owned(c)
+	if !ok {
+		return
+	}
+	imageID, ok := imageID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Caption *string `json:"caption"`
+		Primary *bool   `json:"primary"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if request.Caption == nil && request.Primary == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+	if request.Primary != nil && !*request.Primary {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Make another image primary instead"})
+		return
+	}
+	var caption string
+	if request.Caption != nil {
+		caption = strings.TrimSpace(*request.Caption)
+		if len(caption) > maxCaptionLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("captions can be at most %d characters", maxCaptionLength)})
+			return
+		}
+	}
+
+	kind.modify(c, id, userID, http.StatusOK, func(images []models.Image) ([]models.Image, error) {
+		i, err := imageIndex(images, imageID)
+		if err != nil {
+			return nil, err
+		}
+		if request.Caption != nil {
+			images[i].Caption = caption
+		}
+		if request.Primary != nil {
+			for j := range images {
+				images[j].Primary = j == i
+			}
+		}
+		return images, nil
+	})
+}
+
+func AddLostItemImages(c *gin.Context)     { lostItemImages.add(c) }
+func RemoveLostItemImage(c *gin.Context)   { lostItemImages.remove(c) }
+func ReorderLostItemImages(c *gin.Context) { lostItemImages.reorder(c) }
+func UpdateLostItemImage(c *gin.Context)   { lostItemImages.update(c) }
+
+func AddFoundItemImages(c *gin.Context)     { foundItemImages.add(c) }
+func RemoveFoundItemImage(c *gin.Context)   { foundItemImages.remove(c) }
+func ReorderFoundItemImages(c *gin.Context) { foundItemImages.reorder(c) }
+func UpdateFoundItemImage(c *gin.Context)   { foundItemImages.update(c) }
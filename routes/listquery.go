@@ -0,0 +1,127 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxCategories = 20
+
+var errSortWithSearch = errors.New("sort cannot be combined with near or q")
+
+// listQuery holds the sort and filter parameters shared by the lost and
+// found item listings.
+type listQuery struct {
+	Sort store.SortOrder
+	// From and To bound the date the item was lost or found, inclusive
+	From, To   time.Time
+	State      string
+	Categories []string
+	HasImage   *bool
+	Owner      primitive.ObjectID
+}
+
+// parseListQuery reads the listing parameters:
+//
+//	sort=field[:asc|desc]     one of sortFields, ascending by default
+//	<date>From, <date>To      RFC 3339 timestamps or YYYY-MM-DD dates
+//	state=name                matched whole, ignoring case
+//	category=a,b              repeatable; matches any of the categories
+//	hasImage=true|false
+//	owner=userId
+//
+// date names the date field of the listing, e.g. "dateLost". A To date
+// without a time covers the whole day.
+func parseListQuery(c *gin.Context, date string, sortFields []string) (listQuery, error) {
+	var q listQuery
+	var err error
+
+	if sort := c.Query("sort"); sort != "" {
+		if q.Sort, err = parseSort(sort, sortFields); err != nil {
+			return q, err
+		}
+	}
+
+	if q.From, err = parseDateParam(c, date+"From", false); err != nil {
+		return q, err
+	}
+	if q.To, err = parseDateParam(c, date+"To", true); err != nil {
+		return q, err
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.From.After(q.To) {
+		return q, fmt.Errorf("%sFrom must not be after %sTo", date, date)
+	}
+
+	q.State = strings.TrimSpace(c.Query("state"))
+
+	for _, values := range c.QueryArray("category") {
+		for _, category := range strings.Split(values, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				q.Categories = append(q.Categories, category)
+			}
+		}
+	}
+	if len(q.Categories) > maxCategories {
+		return q, fmt.Errorf("category accepts at most %d values", maxCategories)
+	}
+
+	switch hasImage := c.Query("hasImage"); hasImage {
+	case "":
+	case "true", "false":
+		value := hasImage == "true"
+		q.HasImage = &value
+	default:
+		return q, errors.New("hasImage must be true or false")
+	}
+
+	if owner := c.Query("owner"); owner != "" {
+		if q.Owner, err = primitive.ObjectIDFromHex(owner); err != nil {
+			return q, errors.New("owner must be a user ID")
+		}
+	}
+	return q, nil
+}
+
+// parseSort reads a field[:asc|desc] sort parameter.
+func parseSort(value string, fields []string) (store.SortOrder, error) {
+	field, direction, _ := strings.Cut(value, ":")
+	if !slices.Contains(fields, field) {
+		return store.SortOrder{}, fmt.Errorf("sort field must be one of %s", strings.Join(fields, ", "))
+	}
+	switch direction {
+	case "", "asc":
+		return store.SortOrder{Field: field}, nil
+	case "desc":
+		return store.SortOrder{Field: field, Descending: true}, nil
+	}
+	return store.SortOrder{}, errors.New("sort direction must be asc or desc")
+}
+
+// parseDateParam reads a date query parameter, returning the zero time when
+// it is absent. A plain date is the start of that day in UTC, or its last
+// millisecond when endOfDay is set.
+func parseDateParam(c *gin.Context, name string, endOfDay bool) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC 3339 timestamp", name)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Millisecond)
+	}
+	return t, nil
+}
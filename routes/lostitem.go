@@ -2,18 +2,19 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
-	"lostfound-backend/db"
 	"lostfound-backend/models"
-	"lostfound-backend/utils"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func AddLostItem(c *gin.Context) {
@@ -29,17 +30,10 @@ func AddLostItem(c *gin.Context) {
 		return
 	}
 
-	file, err := c.FormFile("image")
+	// Images are optional; several can be sent as "images"
+	files, captions, err := formImages(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Image is required"})
-		return
-	}
-
-	// Upload to Cloudinary
-	imageURL, err := utils.UploadImage(file)
-	if err != nil || imageURL == "" {
-		log.Println("UploadImage error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid images", "details": err.Error()})
 		return
 	}
 
@@ -57,88 +51,150 @@ func AddLostItem(c *gin.Context) {
 		return
 	}
 
+	position, err := parsePosition(c.PostForm("latitude"), c.PostForm("longitude"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Upload to the image store once the rest of the form is valid
+	images, err := uploadImages(files, captions)
+	if err != nil {
+		imageUploadError(c, err)
+		return
+	}
+
 	item := models.LostItem{
 		Name:        c.PostForm("name"),
 		Description: c.PostForm("description"),
 		Category:    c.PostForm("category"),
-		ImageURL:    imageURL,
 		District:    district,
 		State:       state,
 		Locations:   locations,
+		Position:    position,
 		DateLost:    time.Now(),
+		Status:      models.LostItemOpen,
 		CreatedBy:   objID,
 		CreatedAt:   time.Now(),
 	}
+	item.SetImages(images)
 
-	collection := db.GetCollection("lostitems")
-	result, err := collection.InsertOne(context.Background(), item)
-	if err != nil {
+	if err := store.LostItems.Insert(context.Background(), &item); err != nil {
 		log.Println("InsertOne error:", err)
+		deleteImages(images)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create item"})
 		return
 	}
 
+	matchCount := matchLostItem(&item)
+
 	c.JSON(http.StatusCreated, gin.H{
-		"message":  "Item created successfully",
-		"itemId":   result.InsertedID,
-		"imageURL": imageURL,
+		"message":    "Item created successfully",
+		"itemId":     item.ID,
+		"imageURL":   item.ImageURL,
+		"matchCount": matchCount,
 	})
 }
 
 func GetAllLostItems(c *gin.Context) {
-	collection := db.GetCollection("lostitems")
-	filter := bson.M{}
-
-	// Add filters from query parameters
-	if name := c.Query("name"); name != "" {
-		filter["name"] = bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}
+	// Add filters from query parameters, matched as substrings
+	filter := store.LostItemFilter{
+		Name:     c.Query("name"),
+		District: c.Query("district"),
+	}
+	query, err := parseListQuery(c, "dateLost", store.LostItemSortFields())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Sort = query.Sort
+	filter.LostAfter, filter.LostBefore = query.From, query.To
+	filter.State = query.State
+	filter.Categories = query.Categories
+	filter.HasImage = query.HasImage
+	near, err := parseNear(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Near = near
+	text, err := parseTextQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if text != nil && near != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errTextWithNear.Error()})
+		return
 	}
-	if category := c.Query("category"); category != "" {
-		filter["category"] = bson.M{"$regex": primitive.Regex{Pattern: category, Options: "i"}}
+	filter.Text = text
+	if (near != nil || text != nil) && query.Sort != (store.SortOrder{}) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errSortWithSearch.Error()})
+		return
 	}
-	if district := c.Query("district"); district != "" {
-		filter["district"] = bson.M{"$regex": primitive.Regex{Pattern: district, Options: "i"}}
+
+	// Closed and recovered items are hidden unless asked for by status
+	if statuses := c.Query("status"); statuses != "" {
+		for _, value := range strings.Split(statuses, ",") {
+			status := models.LostItemStatus(strings.TrimSpace(value))
+			if !status.Valid() {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status: " + string(status)})
+				return
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	} else if c.Query("includeClosed") != "true" {
+		filter.Statuses = []models.LostItemStatus{models.LostItemOpen, models.LostItemMatched, models.LostItemExpired}
+	}
+	if !query.Owner.IsZero() {
+		if c.Query("userOnly") == "true" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "owner cannot be combined with userOnly"})
+			return
+		}
+		filter.CreatedBy = query.Owner
+		if canSeeHidden(c, query.Owner) {
+			filter.Visibility = store.AllItems
+		}
 	}
 	if showOnlyUserItems := c.Query("userOnly"); showOnlyUserItems == "true" {
 		if userID, exists := c.Get("userID"); exists {
 			objID, err := primitive.ObjectIDFromHex(userID.(string))
 			if err == nil {
-				filter["createdBy"] = objID
+				filter.CreatedBy = objID
+				filter.Visibility = store.AllItems
 			}
 		}
 	}
 
-	// Add pagination options
-	findOptions := options.Find()
-	if limit := c.Query("limit"); limit != "" {
-		limitInt, err := utils.StringToInt(limit)
-		if err == nil {
-			findOptions.SetLimit(int64(limitInt))
-		}
+	// Searches and sorted listings page by offset; the default newest-first
+	// order pages by keyset
+	keyset := near == nil && text == nil && query.Sort.Default()
+	page, ok := parsePage(c, keyset)
+	if !ok {
+		return
 	}
-	if skip := c.Query("skip"); skip != "" {
-		skipInt, err := utils.StringToInt(skip)
-		if err == nil {
-			findOptions.SetSkip(int64(skipInt))
-		}
+	var key func(models.LostItem) pagination.Cursor
+	if keyset {
+		key = lostItemCursor
 	}
+	filter.After, filter.Skip, filter.Limit = page.After(), page.Skip(), page.Fetch()
 
-	cursor, err := collection.Find(context.Background(), filter, findOptions)
+	result, err := listLostItems(page, key, filter)
 	if err != nil {
 		log.Println("Find error:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
 		return
 	}
-	defer cursor.Close(context.Background())
 
-	var items []models.LostItem
-	if err := cursor.All(context.Background(), &items); err != nil {
-		log.Println("Cursor decode error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode items"})
-		return
-	}
+	c.JSON(http.StatusOK, result)
+}
 
-	c.JSON(http.StatusOK, items)
+// listLostItems loads a page of lost items matching filter.
+func listLostItems(page pagination.Page, key func(models.LostItem) pagination.Cursor, filter store.LostItemFilter) (pagination.Result[models.LostItem], error) {
+	return loadPage(page, key,
+		func() ([]models.LostItem, error) { return store.LostItems.List(context.Background(), filter) },
+		func() (int64, error) { return store.LostItems.Count(context.Background(), filter) },
+	)
 }
 
 func GetLostItemByID(c *gin.Context) {
@@ -149,18 +205,14 @@ func GetLostItemByID(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("lostitems")
-	var item models.LostItem
-	err = collection.FindOne(context.Background(), bson.M{"_id": objID}).Decode(&item)
-	if err != nil {
+	item, err := store.LostItems.FindByID(context.Background(), objID)
+	if err != nil || hiddenFrom(c, item.Hidden, item.CreatedBy) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
 		return
 	}
 
 	// Populate user information
-	var user models.User
-	userCollection := db.GetCollection("users")
-	err = userCollection.FindOne(context.Background(), bson.M{"_id": item.CreatedBy}).Decode(&user)
+	user, err := store.Users.FindByID(context.Background(), item.CreatedBy)
 	if err == nil {
 		item.CreatedByUser = &models.User{
 			Username: user.Username,
@@ -179,41 +231,40 @@ func GetLostItemsByUser(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("lostitems")
-	cursor, err := collection.Find(context.Background(), bson.M{"createdBy": objID})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
+	page, ok := parsePage(c, true)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.Background())
 
-	var items []models.LostItem
-	if err := cursor.All(context.Background(), &items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode items"})
+	filter := store.LostItemFilter{CreatedBy: objID, After: page.After(), Limit: page.Fetch()}
+	if canSeeHidden(c, objID) {
+		filter.Visibility = store.AllItems
+	}
+	result, err := listLostItems(page, lostItemCursor, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
 		return
 	}
 
-	c.JSON(http.StatusOK, items)
+	c.JSON(http.StatusOK, result)
 }
 
 func GetFilterOptions(c *gin.Context) {
-	collection := db.GetCollection("lostitems")
-
-	categories, err := collection.Distinct(context.Background(), "category", bson.M{})
+	categories, err := store.LostItems.Distinct(context.Background(), "category")
 	if err != nil {
 		log.Println("Distinct category error:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
 		return
 	}
 
-	districts, err := collection.Distinct(context.Background(), "district", bson.M{})
+	districts, err := store.LostItems.Distinct(context.Background(), "district")
 	if err != nil {
 		log.Println("Distinct district error:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch districts"})
 		return
 	}
 
-	states, err := collection.Distinct(context.Background(), "state", bson.M{})
+	states, err := store.LostItems.Distinct(context.Background(), "state")
 	if err != nil {
 		log.Println("Distinct state error:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch states"})
@@ -253,38 +304,303 @@ func DeleteLostItem(c *gin.Context) {
 		return
 	}
 
-	collection := db.GetCollection("lostitems")
+	// Delete the item only if it belongs to the user
+	item, err := store.LostItems.DeleteOwned(context.Background(), objID, userObjID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
+		return
+	}
 
-	// First verify the item exists and belongs to the user
-	var item models.LostItem
-	err = collection.FindOne(context.Background(), bson.M{
-		"_id":       objID,
-		"createdBy": userObjID,
-	}).Decode(&item)
+	if err := store.Matches.DeleteByLostItem(context.Background(), objID); err != nil {
+		log.Println("DeleteByLostItem error:", err)
+	}
+	deleteImages(item.ImageList())
 
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Item deleted successfully",
+		"itemId":  itemID,
+	})
+}
+
+func UpdateLostItemStatus(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID format"})
+		return
+	}
+
+	// Get the authenticated user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	var request struct {
+		Status models.LostItemStatus `json:"status" binding:"required"`
+		Note   string                `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if !request.Status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status: " + string(request.Status)})
+		return
+	}
+
+	item, err := store.LostItems.FindByID(context.Background(), objID)
+	if err != nil || item.CreatedBy != userObjID {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
 		return
 	}
 
-	// Delete the item
-	result, err := collection.DeleteOne(context.Background(), bson.M{
-		"_id":       objID,
-		"createdBy": userObjID,
+	err = setLostItemStatus(item, request.Status, userObjID, request.Note)
+	if errors.Is(err, errInvalidTransition) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Cannot change status from " + string(item.CurrentStatus()) + " to " + string(request.Status),
+		})
+		return
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Item status changed, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Status updated successfully",
+		"status":  request.Status,
 	})
+}
 
+var errInvalidTransition = errors.New("invalid status transition")
+
+// setLostItemStatus moves item to status if its lifecycle allows it.
+func setLostItemStatus(item *models.LostItem, status models.LostItemStatus, by primitive.ObjectID, note string) error {
+	from := item.CurrentStatus()
+	if !from.CanTransition(status) {
+		return errInvalidTransition
+	}
+	return store.LostItems.SetStatus(context.Background(), item.ID, models.LostItemStatusChange{
+		From: from,
+		To:   status,
+		At:   time.Now(),
+		By:   by,
+		Note: note,
+	})
+}
+
+// advanceLostItem moves a lost item forward as a side effect of a claim,
+// logging rather than failing when the move does not apply.
+func advanceLostItem(id primitive.ObjectID, status models.LostItemStatus, by primitive.ObjectID, note string) {
+	if id.IsZero() {
+		return
+	}
+	item, err := store.LostItems.FindByID(context.Background(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
 		return
 	}
+	if err := setLostItemStatus(item, status, by, note); err != nil && !errors.Is(err, errInvalidTransition) {
+		log.Println("SetStatus error:", err)
+	}
+}
 
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+func UpdateLostItem(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID format"})
+		return
+	}
+
+	// Get the authenticated user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	// Only fields present in the request are changed
+	var update store.LostItemUpdate
+	var dateLost *string
+	var latitude, longitude *float64
+	if strings.HasPrefix(c.ContentType(), "multipart/") || c.ContentType() == "application/x-www-form-urlencoded" {
+		formString := func(key string) *string {
+			if value, ok := c.GetPostForm(key); ok {
+				return &value
+			}
+			return nil
+		}
+		update.Name = formString("name")
+		update.Description = formString("description")
+		update.Category = formString("category")
+		update.District = formString("district")
+		update.State = formString("state")
+		dateLost = formString("dateLost")
+		for key, target := range map[string]**float64{"latitude": &latitude, "longitude": &longitude} {
+			if value := formString(key); value != nil {
+				f, err := strconv.ParseFloat(*value, 64)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + key})
+					return
+				}
+				*target = &f
+			}
+		}
+		if locations, ok := c.GetPostFormArray("locations"); ok {
+			update.Locations = &locations
+		}
+	} else {
+		var request struct {
+			Name        *string   `json:"name"`
+			Description *string   `json:"description"`
+			Category    *string   `json:"category"`
+			District    *string   `json:"district"`
+			State       *string   `json:"state"`
+			Locations   *[]string `json:"locations"`
+			DateLost    *string   `json:"dateLost"`
+			Latitude    *float64  `json:"latitude"`
+			Longitude   *float64  `json:"longitude"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+			return
+		}
+		update.Name = request.Name
+		update.Description = request.Description
+		update.Category = request.Category
+		update.District = request.District
+		update.State = request.State
+		update.Locations = request.Locations
+		dateLost = request.DateLost
+		latitude = request.Latitude
+		longitude = request.Longitude
+	}
+
+	for _, required := range []struct {
+		field string
+		value *string
+	}{{"name", update.Name}, {"district", update.District}, {"state", update.State}} {
+		if required.value != nil && strings.TrimSpace(*required.value) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The " + required.field + " field cannot be empty"})
+			return
+		}
+	}
+	if update.Locations != nil {
+		locations := []string{}
+		for _, location := range *update.Locations {
+			if location = strings.TrimSpace(location); location != "" {
+				locations = append(locations, location)
+			}
+		}
+		update.Locations = &locations
+	}
+	if dateLost != nil {
+		t, err := time.Parse(time.RFC3339, *dateLost)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dateLost must be an RFC 3339 timestamp"})
+			return
+		}
+		update.DateLost = &t
+	}
+
+	if latitude != nil || longitude != nil {
+		if latitude == nil || longitude == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "latitude and longitude must be updated together"})
+			return
+		}
+		position, err := models.NewGeoPoint(*latitude, *longitude)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		update.Position = position
+	}
+
+	// Verify ownership before uploading anything
+	item, err := store.LostItems.FindByID(context.Background(), objID)
+	if err != nil || item.CreatedBy != userObjID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
 		return
 	}
 
+	if update.Empty() {
+		if _, err := c.FormFile("image"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+			return
+		}
+	}
+
+	// A single "image" replaces the primary image, keeping its caption
+	var added, replaced []models.Image
+	if file, err := c.FormFile("image"); err == nil {
+		image, err := uploadImage(file)
+		if err != nil {
+			imageUploadError(c, err)
+			return
+		}
+		images := item.ImageList()
+		for i := range images {
+			if images[i].Primary {
+				image.Caption = images[i].Caption
+				image.Primary = true
+				replaced = append(replaced, images[i])
+				images[i] = *image
+			}
+		}
+		if len(replaced) == 0 {
+			images = append([]models.Image{*image}, images...)
+		}
+		added = append(added, *image)
+		update.Images = &images
+		update.ImagesVersion = item.ImagesVersion
+	}
+
+	updated, err := store.LostItems.UpdateOwned(context.Background(), objID, userObjID, update)
+	if err != nil {
+		deleteImages(added)
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found or not owned by user"})
+		return
+	}
+	if errors.Is(err, store.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The item's images were changed by another request, try again"})
+		return
+	}
+	if err != nil {
+		log.Println("UpdateOwned error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+	deleteImages(replaced)
+
+	// Re-score the edited report against found items while it is still open
+	if status := updated.CurrentStatus(); status == models.LostItemOpen || status == models.LostItemMatched {
+		matchLostItem(updated)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Item deleted successfully",
-		"itemId":  itemID,
+		"message": "Item updated successfully",
+		"item":    updated,
 	})
 }
@@ -0,0 +1,246 @@
+package routes
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+
+	"lostfound-backend/imageproc"
+	"lostfound-backend/matching"
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetLostItemMatches(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if item, err := store.LostItems.FindByID(context.Background(), objID); err != nil || hiddenFrom(c, item.Hidden, item.CreatedBy) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	page, ok := parsePage(c, false)
+	if !ok {
+		return
+	}
+	result, err := listMatches(c, page, store.MatchFilter{LostItem: objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
+		return
+	}
+
+	// Populate found item information. An item hidden or deleted since the
+	// page was read is still dropped
+	results := []models.Match{}
+	for _, match := range result.Items {
+		item, err := store.FoundItems.FindByID(context.Background(), match.FoundItem)
+		if err != nil || hiddenFrom(c, item.Hidden, item.FoundPerson) {
+			continue
+		}
+		match.FoundItemDetails = item
+		results = append(results, match)
+	}
+	result.Items = results
+
+	c.JSON(http.StatusOK, result)
+}
+
+func GetFoundItemMatches(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if item, err := store.FoundItems.FindByID(context.Background(), objID); err != nil || hiddenFrom(c, item.Hidden, item.FoundPerson) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	page, ok := parsePage(c, false)
+	if !ok {
+		return
+	}
+	result, err := listMatches(c, page, store.MatchFilter{FoundItem: objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
+		return
+	}
+
+	// Populate lost item information. An item hidden or deleted since the
+	// page was read is still dropped
+	results := []models.Match{}
+	for _, match := range result.Items {
+		item, err := store.LostItems.FindByID(context.Background(), match.LostItem)
+		if err != nil || hiddenFrom(c, item.Hidden, item.CreatedBy) {
+			continue
+		}
+		match.LostItemDetails = item
+		results = append(results, match)
+	}
+	result.Items = results
+
+	c.JSON(http.StatusOK, result)
+}
+
+// listMatches loads a page of the matches the current user can see, best
+// score first: those whose other item is gone or hidden from them are left
+// out of both the page and the total.
+func listMatches(c *gin.Context, page pagination.Page, filter store.MatchFilter) (pagination.Result[models.Match], error) {
+	viewer, _ := primitive.ObjectIDFromHex(c.GetString("userID"))
+	filter.Viewer = &store.MatchViewer{User: viewer, Staff: currentRole(c).Staff()}
+	filter.Limit, filter.Skip = page.Fetch(), page.Skip()
+	return loadPage(page, nil,
+		func() ([]models.Match, error) { return store.Matches.List(context.Background(), filter) },
+		func() (int64, error) { return store.Matches.Count(context.Background(), filter) },
+	)
+}
+
+const (
+	// maxImageDistance is the largest hash distance, out of 64 bits, at
+	// which two photos still count as similar.
+	maxImageDistance = 12
+	// maxSimilarCandidates caps how many found items, newest first, a
+	// similarity search compares against.
+	maxSimilarCandidates = 500
+)
+
+// GetSimilarFoundItems lists unreturned found items whose photos look like
+// the lost item's, closest first. Items are compared by the smallest hash
+// distance between any photo of one and any photo of the other. Only the
+// newest maxSimilarCandidates items found in the lost item's matching date
+// window are compared; the result is paged by offset.
+func GetSimilarFoundItems(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	page, ok := parsePage(c, false)
+	if !ok {
+		return
+	}
+
+	lostItem, err := store.LostItems.FindByID(context.Background(), objID)
+	if err != nil || hiddenFrom(c, lostItem.Hidden, lostItem.CreatedBy) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	results := []models.FoundItem{}
+	hashes := imageHashes(lostItem.Images)
+	if len(hashes) > 0 {
+		notFound := false
+		candidates, err := store.FoundItems.List(context.Background(), store.FoundItemFilter{
+			Found:        &notFound,
+			HashedImages: true,
+			FoundAfter:   lostItem.DateLost.Add(-matching.DateSlack),
+			FoundBefore:  lostItem.DateLost.Add(matching.DateWindow),
+			Limit:        maxSimilarCandidates,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch found items"})
+			return
+		}
+
+		for _, item := range candidates {
+			distance := -1
+			for _, hash := range imageHashes(item.Images) {
+				for _, own := range hashes {
+					if d := own.Distance(hash); distance < 0 || d < distance {
+						distance = d
+					}
+				}
+			}
+			if distance < 0 || distance > maxImageDistance {
+				continue
+			}
+			item.ImageDistance = &distance
+			results = append(results, item)
+		}
+
+		sort.SliceStable(results, func(i, j int) bool {
+			return *results[i].ImageDistance < *results[j].ImageDistance
+		})
+	}
+
+	result, _ := loadPage(page, nil, func() ([]models.FoundItem, error) {
+		return pageOf(results, page), nil
+	}, func() (int64, error) {
+		return int64(len(results)), nil
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+// imageHashes returns the perceptual hashes of images, skipping those
+// uploaded before hashing.
+func imageHashes(images []models.Image) []imageproc.Hash {
+	var hashes []imageproc.Hash
+	for _, image := range images {
+		if image.Hash == "" {
+			continue
+		}
+		hash, err := imageproc.ParseHash(image.Hash)
+		if err != nil {
+			log.Println("Image hash error:", err)
+			continue
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// matchFoundItem records candidate matches for a new or edited found item
+// and tells the owner of each newly matched lost item about it; owners
+// already told about the pair are not told again when the item is edited.
+// Failures are only logged so the report itself still succeeds.
+func matchFoundItem(item *models.FoundItem) {
+	previous, err := store.Matches.ListByFoundItem(context.Background(), item.ID)
+	if err != nil {
+		log.Println("ListByFoundItem error:", err)
+		return
+	}
+	known := make(map[primitive.ObjectID]bool, len(previous))
+	for _, match := range previous {
+		known[match.LostItem] = true
+	}
+
+	matches, err := matching.MatchFoundItem(context.Background(), item)
+	if err != nil {
+		log.Println("MatchFoundItem error:", err)
+	}
+
+	for _, match := range matches {
+		if known[match.LostItem] || match.LostItemDetails.CreatedBy == item.FoundPerson {
+			continue
+		}
+		notifyUser(models.Notification{
+			User:      match.LostItemDetails.CreatedBy,
+			Type:      models.NotificationMatch,
+			Actor:     item.FoundPerson,
+			LostItem:  match.LostItem,
+			FoundItem: item.ID,
+			Message:   "A found item may match your lost item \"" + match.LostItemDetails.Name + "\"",
+		})
+	}
+}
+
+// matchLostItem records candidate matches for a new lost item and returns
+// how many were found.
+func matchLostItem(item *models.LostItem) int {
+	matches, err := matching.MatchLostItem(context.Background(), item)
+	if err != nil {
+		log.Println("MatchLostItem error:", err)
+	}
+	return len(matches)
+}
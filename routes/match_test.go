@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLostItemMatchesLeaveOutHiddenAndDeletedItems(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	owner, finder := primitive.NewObjectID(), primitive.NewObjectID()
+	lost := models.LostItem{Name: "wallet", CreatedBy: owner}
+	if err := store.LostItems.Insert(ctx, &lost); err != nil {
+		t.Fatal(err)
+	}
+
+	// The best matches are with a hidden item and one since deleted
+	hidden := models.FoundItem{Name: "wallet", FoundPerson: finder, Hidden: &models.ModerationAction{Reason: "spam"}}
+	visible := models.FoundItem{Name: "wallet", FoundPerson: finder}
+	for _, item := range []*models.FoundItem{&hidden, &visible} {
+		if err := store.FoundItems.Insert(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, match := range []models.Match{
+		{LostItem: lost.ID, FoundItem: hidden.ID, Score: 0.9},
+		{LostItem: lost.ID, FoundItem: primitive.NewObjectID(), Score: 0.8},
+		{LostItem: lost.ID, FoundItem: visible.ID, Score: 0.7},
+	} {
+		if err := store.Matches.Upsert(ctx, &match); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		viewer primitive.ObjectID
+		want   []primitive.ObjectID
+	}{
+		"owner":  {owner, []primitive.ObjectID{visible.ID}},
+		"finder": {finder, []primitive.ObjectID{hidden.ID, visible.ID}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := call(t, GetLostItemMatches, http.MethodGet, "/lostitems/:id/matches",
+				"/lostitems/"+lost.ID.Hex()+"/matches?limit=1&count=true", tt.viewer, nil)
+			expectStatus(t, w, http.StatusOK)
+			var result pagination.Result[models.Match]
+			decode(t, w, &result)
+			if len(result.Items) != 1 || result.Items[0].FoundItem != tt.want[0] {
+				t.Errorf("first page = %v, want the match with %v", result.Items, tt.want[0].Hex())
+			}
+			if result.TotalCount == nil {
+				t.Fatal("no total in the response")
+			}
+			if *result.TotalCount != int64(len(tt.want)) {
+				t.Errorf("total = %d, want %d", *result.TotalCount, len(tt.want))
+			}
+			if (result.NextCursor != "") != (len(tt.want) > 1) {
+				t.Errorf("next cursor %q with %d visible matches", result.NextCursor, len(tt.want))
+			}
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package routes
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"strings"
 
+	"lostfound-backend/models"
+	"lostfound-backend/store"
 	"lostfound-backend/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -51,10 +55,90 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Tokens without a jti predate revocation support and can't be
+		// logged out, so make their holders sign in again
+		jti, _ := claims["jti"].(string)
+		sessionID, _ := claims["sid"].(string)
+		if jti == "" || sessionID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token is no longer supported, please log in again"})
+			c.Abort()
+			return
+		}
+		family, _ := primitive.ObjectIDFromHex(sessionID)
+		revoked, err := store.RevokedTokens.IsRevoked(context.Background(), jti, family)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify token"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+
+		// Tokens issued before roles existed carry none
+		role, _ := claims["role"].(string)
+		if role == "" {
+			role = string(models.RoleUser)
+		}
+
 		// Store the userId in the context for further use in the route handlers
 		c.Set("userID", userID)
+		c.Set("sessionID", sessionID)
+		c.Set("role", role)
 
 		// Proceed with the next middleware/handler
 		c.Next()
 	}
 }
+
+// TokenFromQuery lets a route accept the JWT as ?token= for clients that
+// cannot set headers, such as the browser EventSource API. It must run
+// before AuthMiddleware and only on routes that need it, since query
+// strings end up in access logs.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
+
+// currentUser returns the authenticated user's ID. On failure it writes the
+// response and returns false.
+func currentUser(c *gin.Context) (primitive.ObjectID, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return primitive.NilObjectID, false
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return primitive.NilObjectID, false
+	}
+	return userObjID, true
+}
+
+// RequireRole lets through only users whose token carries one of roles. It
+// must run after AuthMiddleware.
+func RequireRole(roles ...models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := currentRole(c)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to do this"})
+		c.Abort()
+	}
+}
+
+// currentRole returns the authenticated user's role from their token.
+func currentRole(c *gin.Context) models.Role {
+	return models.Role(c.GetString("role"))
+}
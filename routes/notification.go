@@ -0,0 +1,278 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/notify"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// notificationReplayLimit caps how many missed notifications are
+	// replayed to a reconnecting stream; older ones are left to the list.
+	notificationReplayLimit = 50
+	heartbeatInterval       = 25 * time.Second
+	streamRetry             = 5 * time.Second
+)
+
+// notifications delivers new notifications to open notification streams.
+var notifications = notify.NewHub()
+
+// notifyUser stores a notification for n.User and pushes it to their open
+// streams. Failures are only logged; a missed notification never fails the
+// request that caused it.
+func notifyUser(n models.Notification) {
+	if n.User.IsZero() {
+		return
+	}
+	n.Read = false
+	n.CreatedAt = time.Now()
+	if err := store.Notifications.Insert(context.Background(), &n); err != nil {
+		log.Println("Insert notification error:", err)
+		return
+	}
+	notifications.Publish(n.User.Hex(), notify.Event{
+		ID:   notificationCursor(n).Encode(),
+		Type: "notification",
+		Data: n,
+	})
+}
+
+// StreamNotifications holds the connection open as a Server-Sent Events
+// stream and writes each new notification for the user as it is created.
+// Event IDs are notification cursors, so a client reconnecting with
+// Last-Event-ID first receives what it missed from the stored notifications,
+// whichever instance it reconnects to. The stream ends when its session is
+// logged out or revoked.
+func StreamNotifications(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	session := c.GetString("sessionID")
+	family, err := primitive.ObjectIDFromHex(session)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session"})
+		return
+	}
+
+	// An ID this server did not issue cannot be resumed from; the client
+	// just gets new notifications, as on a first connect
+	var since *pagination.Cursor
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		if cursor, err := pagination.Decode(header); err == nil && cursor.Keyset() {
+			since = cursor
+		}
+	}
+
+	// Subscribe before reading the missed notifications, so none created
+	// in between is lost; one delivered both ways is skipped below
+	sub := notifications.Subscribe(userObjID.Hex(), session)
+	defer sub.Close()
+
+	var missed []models.Notification
+	if since != nil {
+		var err error
+		missed, err = store.Notifications.List(context.Background(), store.NotificationFilter{
+			User:  userObjID,
+			Since: since,
+			Limit: notificationReplayLimit,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+			return
+		}
+	}
+	replayed := make(map[string]bool, len(missed))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // stop nginx buffering the stream
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+	// List is newest first; replay in the order they were created
+	for i := len(missed) - 1; i >= 0; i-- {
+		event := notify.Event{ID: notificationCursor(missed[i]).Encode(), Type: "notification", Data: missed[i]}
+		replayed[event.ID] = true
+		if err := writeEvent(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind, or the session was revoked.
+				// A live client reconnects and resumes from the stored
+				// notifications; a revoked one is refused by the middleware
+				return
+			}
+			if replayed[event.ID] {
+				continue
+			}
+			if err := writeEvent(c.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			// Revocations on other instances only reach this one through
+			// the store
+			revoked, err := store.RevokedTokens.IsRevoked(context.Background(), "", family)
+			if err != nil {
+				log.Println("Check stream session error:", err)
+			}
+			if revoked {
+				return
+			}
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes one event in the text/event-stream format.
+func writeEvent(w gin.ResponseWriter, event notify.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		log.Println("Marshal event error:", err)
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// ListNotifications returns a page of the user's notifications, newest
+// first. unread=true leaves out the ones already read.
+func ListNotifications(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	filter := store.NotificationFilter{
+		User:       userObjID,
+		UnreadOnly: c.Query("unread") == "true",
+		After:      page.After(),
+		Limit:      page.Fetch(),
+	}
+	result, err := loadPage(page, notificationCursor,
+		func() ([]models.Notification, error) { return store.Notifications.List(context.Background(), filter) },
+		func() (int64, error) { return store.Notifications.Count(context.Background(), filter) },
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func notificationCursor(n models.Notification) pagination.Cursor {
+	return pagination.After(n.CreatedAt, n.ID)
+}
+
+func GetUnreadNotificationCount(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	count, err := store.Notifications.CountUnread(context.Background(), userObjID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"count": count})
+}
+
+func MarkNotificationRead(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	notificationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = store.Notifications.MarkRead(context.Background(), notificationID, userObjID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+func MarkAllNotificationsRead(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	updated, err := store.Notifications.MarkAllRead(context.Background(), userObjID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "All notifications marked as read",
+		"updated": updated,
+	})
+}
+
+func DeleteNotification(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	notificationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = store.Notifications.DeleteOwned(context.Background(), notificationID, userObjID)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification deleted successfully"})
+}
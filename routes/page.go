@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"net/http"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePage reads the limit, cursor and count parameters of a paginated
+// listing. keyset says whether the listing is in creation order; listings
+// sorted by distance or relevance page by offset instead. On failure it
+// writes the response and returns false.
+func parsePage(c *gin.Context, keyset bool) (pagination.Page, bool) {
+	page, err := pagination.Parse(c.Query("limit"), c.Query("cursor"), c.Query("count"), keyset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return page, false
+	}
+	return page, true
+}
+
+// loadPage loads a page with list, which must fetch page.Fetch() items, and
+// the total with count when the client asked for it. key is nil for
+// listings paged by offset.
+func loadPage[T any](page pagination.Page, key func(T) pagination.Cursor, list func() ([]T, error), count func() (int64, error)) (pagination.Result[T], error) {
+	items, err := list()
+	if err != nil {
+		return pagination.Result[T]{}, err
+	}
+	result := pagination.NewResult(page, items, key)
+	if page.Count {
+		total, err := count()
+		if err != nil {
+			return pagination.Result[T]{}, err
+		}
+		result.TotalCount = &total
+	}
+	return result, nil
+}
+
+func lostItemCursor(item models.LostItem) pagination.Cursor {
+	return pagination.After(item.CreatedAt, item.ID)
+}
+
+func foundItemCursor(item models.FoundItem) pagination.Cursor {
+	return pagination.After(item.CreatedAt, item.ID)
+}
+
+// pageOf returns the part of items, a listing built in memory, that page
+// loads with an offset cursor.
+func pageOf[T any](items []T, page pagination.Page) []T {
+	start := min(page.Skip(), int64(len(items)))
+	end := min(start+page.Fetch(), int64(len(items)))
+	return items[start:end]
+}
@@ -0,0 +1,162 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"lostfound-backend/mailer"
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+	"lostfound-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordResetTTL is how long a reset link stays valid.
+const passwordResetTTL = time.Hour
+
+// ForgotPassword mails a password reset link to the account with the given
+// email. It answers the same way whether or not the account exists, so it
+// cannot be used to find out who is registered.
+func ForgotPassword(c *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	response := gin.H{"message": "If an account exists for that email, a reset link has been sent"}
+
+	user, err := store.Users.FindByEmail(context.Background(), request.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start password reset"})
+		return
+	}
+
+	token, err := issueOneTimeToken(user.ID, models.TokenPasswordReset, "", passwordResetTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start password reset"})
+		return
+	}
+
+	sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Someone asked to reset the password for your account. If it was you, open this link within the next hour:\n\n" +
+			appLink("/reset-password", token) + "\n\n" +
+			"If you did not ask for this, you can ignore this email.\n",
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword. The
+// token works once, and every existing session of the account is ended.
+func ResetPassword(c *gin.Context) {
+	var request struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	token, err := store.OneTimeTokens.Consume(context.Background(),
+		models.TokenPasswordReset, utils.HashToken(request.Token), time.Now())
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to secure password"})
+		return
+	}
+	err = store.Users.SetPassword(context.Background(), token.User, string(hashedPassword))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	if err := revokeUserSessions(token.User); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Password changed, but failed to end existing sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// issueOneTimeToken replaces the user's outstanding tokens for purpose with
+// a new one valid for ttl and returns the token to mail to them. email is
+// recorded for tokens that confirm an address.
+func issueOneTimeToken(userID primitive.ObjectID, purpose models.TokenPurpose, email string, ttl time.Duration) (string, error) {
+	if err := store.OneTimeTokens.DeleteForUser(context.Background(), userID, purpose); err != nil {
+		log.Println("DeleteForUser error:", err)
+		return "", err
+	}
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	err = store.OneTimeTokens.Insert(context.Background(), &models.OneTimeToken{
+		User:      userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		Email:     email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		log.Println("Insert one-time token error:", err)
+		return "", err
+	}
+	return token, nil
+}
+
+// sendMail delivers msg in the background so a slow mail server neither
+// delays the response nor shows in its timing. Failures are only logged.
+func sendMail(msg mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := mailer.Send(ctx, msg); err != nil {
+			log.Printf("Failed to send %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
+
+// appLink builds a link into the web app carrying token as a query
+// parameter. APP_URL is the app's base URL.
+func appLink(path, token string) string {
+	base := os.Getenv("APP_URL")
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	return strings.TrimRight(base, "/") + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,437 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+	"lostfound-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// defaultReportHideThreshold is how many users must report the same item
+	// or message before it is hidden pending review
+	defaultReportHideThreshold = 3
+	maxReportDetailsLength     = 1000
+)
+
+// reportHideThreshold reads REPORT_HIDE_THRESHOLD, falling back to the default.
+func reportHideThreshold() int64 {
+	if v := os.Getenv("REPORT_HIDE_THRESHOLD"); v != "" {
+		n, err := utils.StringToInt(v)
+		if err == nil && n > 0 {
+			return int64(n)
+		}
+		log.Printf("⚠️ Invalid REPORT_HIDE_THRESHOLD %q, using %d", v, defaultReportHideThreshold)
+	}
+	return defaultReportHideThreshold
+}
+
+// CreateReport flags a lost or found item, a user or a message for the
+// moderators. Once enough different users have open reports on an item or
+// message it is hidden until a moderator reviews it.
+func CreateReport(c *gin.Context) {
+	userObjID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		TargetType models.TargetType   `json:"targetType" binding:"required"`
+		TargetID   string              `json:"targetId" binding:"required"`
+		Reason     models.ReportReason `json:"reason" binding:"required"`
+		Details    string              `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if !request.TargetType.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid targetType: " + string(request.TargetType)})
+		return
+	}
+	if !request.Reason.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reason: " + string(request.Reason)})
+		return
+	}
+	details := strings.TrimSpace(request.Details)
+	if request.Reason == models.ReportOther && details == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Details are required when the reason is other"})
+		return
+	}
+	if len(details) > maxReportDetailsLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Details are too long"})
+		return
+	}
+	targetID, err := primitive.ObjectIDFromHex(request.TargetID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+		return
+	}
+
+	owner, ok := reportTargetOwner(c, userObjID, request.TargetType, targetID)
+	if !ok {
+		return
+	}
+	if owner == userObjID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot report yourself"})
+		return
+	}
+
+	report := models.Report{
+		TargetType:  request.TargetType,
+		Target:      targetID,
+		TargetOwner: owner,
+		Reporter:    userObjID,
+		Reason:      request.Reason,
+		Details:     details,
+		Status:      models.ReportOpen,
+		CreatedAt:   time.Now(),
+	}
+	err = store.Reports.Insert(context.Background(), &report)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already reported this"})
+		return
+	}
+	if err != nil {
+		log.Println("Insert report error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report"})
+		return
+	}
+
+	count, err := store.Reports.CountOpen(context.Background(), report.TargetType, report.Target)
+	if err != nil {
+		log.Println("CountOpen error:", err)
+	} else if threshold := reportHideThreshold(); count >= threshold {
+		autoHide(report.TargetType, report.Target, owner, count)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Report submitted",
+		"report":  report,
+	})
+}
+
+// reportTargetOwner checks that the reporter can see the target and returns
+// who posted it. On failure it writes the response and returns false.
+func reportTargetOwner(c *gin.Context, reporter primitive.ObjectID, targetType models.TargetType, id primitive.ObjectID) (primitive.ObjectID, bool) {
+	notFound := func() (primitive.ObjectID, bool) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report target not found"})
+		return primitive.NilObjectID, false
+	}
+
+	switch targetType {
+	case models.TargetLostItem:
+		item, err := store.LostItems.FindByID(context.Background(), id)
+		if err != nil || hiddenFrom(c, item.Hidden, item.CreatedBy) {
+			return notFound()
+		}
+		return item.CreatedBy, true
+	case models.TargetFoundItem:
+		item, err := store.FoundItems.FindByID(context.Background(), id)
+		if err != nil || hiddenFrom(c, item.Hidden, item.FoundPerson) {
+			return notFound()
+		}
+		return item.FoundPerson, true
+	case models.TargetUser:
+		if _, err := store.Users.FindByID(context.Background(), id); err != nil {
+			return notFound()
+		}
+		return id, true
+	case models.TargetMessage:
+		// Only the two participants have seen a message
+		message, err := store.Messages.FindByID(context.Background(), id)
+		if err != nil {
+			return notFound()
+		}
+		conv, err := store.Conversations.FindByID(context.Background(), message.Conversation)
+		if err != nil || !conv.HasParticipant(reporter) {
+			return notFound()
+		}
+		return message.Sender, true
+	}
+	return notFound()
+}
+
+// autoHide hides a heavily reported item or message until a moderator
+// reviews it. Users are never suspended automatically; their reports just
+// wait in the queue.
+func autoHide(targetType models.TargetType, target, owner primitive.ObjectID, count int64) {
+	reason := fmt.Sprintf("Hidden automatically after %d reports", count)
+	hidden := &models.ModerationAction{At: time.Now(), Reason: reason}
+
+	var err error
+	var notification models.Notification
+	switch targetType {
+	case models.TargetLostItem:
+		item, findErr := store.LostItems.FindByID(context.Background(), target)
+		if findErr != nil || item.Hidden != nil {
+			return
+		}
+		err = store.LostItems.SetHidden(context.Background(), target, hidden)
+		notification = models.Notification{LostItem: target, Message: "Your lost item report \"" + item.Name + "\" was hidden while moderators review reports about it"}
+	case models.TargetFoundItem:
+		item, findErr := store.FoundItems.FindByID(context.Background(), target)
+		if findErr != nil || item.Hidden != nil {
+			return
+		}
+		err = store.FoundItems.SetHidden(context.Background(), target, hidden)
+		notification = models.Notification{FoundItem: target, Message: "Your found item report \"" + item.Name + "\" was hidden while moderators review reports about it"}
+	case models.TargetMessage:
+		message, findErr := store.Messages.FindByID(context.Background(), target)
+		if findErr != nil || message.Hidden != nil {
+			return
+		}
+		err = store.Messages.SetHidden(context.Background(), target, hidden)
+		notification = models.Notification{Message: "One of your messages was hidden while moderators review reports about it"}
+	default:
+		return
+	}
+	if err != nil {
+		log.Println("Auto-hide error:", err)
+		return
+	}
+
+	recordAudit(primitive.NilObjectID, models.AuditAutoHide, targetType, target, reason)
+	notification.User = owner
+	notification.Type = models.NotificationModeration
+	notifyUser(notification)
+}
+
+// AdminListReports is the moderation queue: open reports, oldest first,
+// unless ?status= asks for closed ones. It pages by offset, since keyset
+// cursors only run newest first.
+func AdminListReports(c *gin.Context) {
+	filter := store.ReportFilter{Status: models.ReportOpen}
+	switch status := models.ReportStatus(c.DefaultQuery("status", string(models.ReportOpen))); {
+	case status == "all":
+		filter.Status = ""
+	case status.Valid():
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status: " + string(status)})
+		return
+	}
+	if targetType := models.TargetType(c.Query("targetType")); targetType != "" {
+		if !targetType.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid targetType: " + string(targetType)})
+			return
+		}
+		filter.TargetType = targetType
+	}
+	if target := c.Query("target"); target != "" {
+		targetID, err := primitive.ObjectIDFromHex(target)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+			return
+		}
+		filter.Target = targetID
+	}
+	page, ok := parsePage(c, false)
+	if !ok {
+		return
+	}
+	filter.Limit, filter.Skip = page.Fetch(), page.Skip()
+
+	result, err := loadPage(page, nil,
+		func() ([]models.Report, error) { return store.Reports.List(context.Background(), filter) },
+		func() (int64, error) { return store.Reports.Count(context.Background(), filter) },
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ResolveReport closes every open report on the report's target as acted
+// upon. Moderators take the action itself (hiding, deleting, suspending)
+// through the other admin endpoints.
+func ResolveReport(c *gin.Context) {
+	closeReports(c, models.ReportResolved)
+}
+
+// DismissReport closes every open report on the report's target as
+// unfounded, and shows the target again if it was hidden automatically.
+func DismissReport(c *gin.Context) {
+	closeReports(c, models.ReportDismissed)
+}
+
+func closeReports(c *gin.Context, status models.ReportStatus) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	reportID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+	var request struct {
+		Note string `json:"note"`
+	}
+	// The note is optional, so an empty body is fine
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+			return
+		}
+	}
+
+	report, err := store.Reports.FindByID(context.Background(), reportID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+	if report.Status != models.ReportOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Report is already " + string(report.Status)})
+		return
+	}
+
+	decision := models.ModerationAction{By: moderatorID, At: time.Now(), Reason: request.Note}
+	closed, err := store.Reports.Close(context.Background(), report.TargetType, report.Target, status, decision)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reports"})
+		return
+	}
+
+	if status == models.ReportDismissed {
+		recordAudit(moderatorID, models.AuditDismissReports, report.TargetType, report.Target, request.Note)
+		restoreAutoHidden(moderatorID, report.TargetType, report.Target)
+	} else {
+		recordAudit(moderatorID, models.AuditResolveReports, report.TargetType, report.Target, request.Note)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reports " + string(status),
+		"closed":  closed,
+	})
+}
+
+// restoreAutoHidden shows a target again if autoHide hid it. Targets a
+// moderator hid by hand stay hidden.
+func restoreAutoHidden(moderatorID primitive.ObjectID, targetType models.TargetType, target primitive.ObjectID) {
+	var hidden *models.ModerationAction
+	var setHidden func(context.Context, primitive.ObjectID, *models.ModerationAction) error
+	switch targetType {
+	case models.TargetLostItem:
+		if item, err := store.LostItems.FindByID(context.Background(), target); err == nil {
+			hidden, setHidden = item.Hidden, store.LostItems.SetHidden
+		}
+	case models.TargetFoundItem:
+		if item, err := store.FoundItems.FindByID(context.Background(), target); err == nil {
+			hidden, setHidden = item.Hidden, store.FoundItems.SetHidden
+		}
+	case models.TargetMessage:
+		if message, err := store.Messages.FindByID(context.Background(), target); err == nil {
+			hidden, setHidden = message.Hidden, store.Messages.SetHidden
+		}
+	}
+	if hidden == nil || !hidden.By.IsZero() {
+		return
+	}
+	if err := setHidden(context.Background(), target, nil); err != nil {
+		log.Println("Unhide error:", err)
+		return
+	}
+	recordAudit(moderatorID, models.AuditUnhide, targetType, target, "Reports dismissed")
+}
+
+// AdminSetMessageHidden hides a message's body from its conversation, or
+// shows it again.
+func AdminSetMessageHidden(c *gin.Context) {
+	moderatorID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+	var request hideRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	var hidden *models.ModerationAction
+	if *request.Hidden {
+		hidden = &models.ModerationAction{By: moderatorID, At: time.Now(), Reason: request.Reason}
+	}
+	err = store.Messages.SetHidden(context.Background(), objID, hidden)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update message"})
+		return
+	}
+	recordAudit(moderatorID, hideAction(hidden), models.TargetMessage, objID, request.Reason)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Message updated successfully", "hidden": *request.Hidden})
+}
+
+// GetAuditLog lists moderation decisions, newest first, optionally only
+// those by ?actor= or about ?target=.
+func GetAuditLog(c *gin.Context) {
+	var filter store.AuditFilter
+	for key, target := range map[string]*primitive.ObjectID{"actor": &filter.Actor, "target": &filter.Target} {
+		if value := c.Query(key); value != "" {
+			id, err := primitive.ObjectIDFromHex(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + key + " ID"})
+				return
+			}
+			*target = id
+		}
+	}
+	page, ok := parsePage(c, true)
+	if !ok {
+		return
+	}
+	filter.After, filter.Limit = page.After(), page.Fetch()
+
+	result, err := loadPage(page, auditCursor,
+		func() ([]models.AuditEntry, error) { return store.AuditLog.List(context.Background(), filter) },
+		func() (int64, error) { return store.AuditLog.Count(context.Background(), filter) },
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func auditCursor(entry models.AuditEntry) pagination.Cursor {
+	return pagination.After(entry.CreatedAt, entry.ID)
+}
+
+// recordAudit appends a moderation decision to the audit log. Failures are
+// only logged so the decision itself still stands.
+func recordAudit(actor primitive.ObjectID, action models.AuditAction, targetType models.TargetType, target primitive.ObjectID, note string) {
+	err := store.AuditLog.Insert(context.Background(), &models.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		TargetType: targetType,
+		Target:     target,
+		Note:       note,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		log.Println("Insert audit entry error:", err)
+	}
+}
@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// useMemory gives the test a fresh, empty in-memory store.
+func useMemory(t *testing.T) {
+	t.Helper()
+	store.UseMemory()
+}
+
+// formBody is a request body sent with its own content type, such as a
+// multipart form.
+type formBody struct {
+	data        io.Reader
+	contentType string
+}
+
+// call runs handler for one request as user, who is anonymous when zero.
+// pattern is the route the handler is mounted on, target the requested
+// path, and body is sent as JSON unless it is a formBody.
+func call(t *testing.T, handler gin.HandlerFunc, method, pattern, target string, user primitive.ObjectID, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case formBody:
+		reader = b.data
+		contentType = b.contentType
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if contentType != "" && reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	router := gin.New()
+	router.Handle(method, pattern, func(c *gin.Context) {
+		if !user.IsZero() {
+			c.Set("userID", user.Hex())
+			c.Set("role", string(models.RoleUser))
+		}
+	}, handler)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decode reads a JSON response body into v.
+func decode(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("response %q: %v", w.Body.String(), err)
+	}
+}
+
+// expectStatus fails the test unless the response has status.
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status %d (%s), want %d %s", w.Code, w.Body.String(), status, http.StatusText(status))
+	}
+}
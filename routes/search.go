@@ -0,0 +1,114 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/store"
+	"lostfound-backend/textsearch"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxSearchLength = 200
+
+var errTextWithNear = errors.New("q cannot be combined with near")
+
+// parseTextQuery reads the q= full-text search parameter. It returns nil
+// when no q parameter was given.
+func parseTextQuery(c *gin.Context) (*textsearch.Query, error) {
+	raw := strings.TrimSpace(c.Query("q"))
+	if raw == "" {
+		return nil, nil
+	}
+	if len(raw) > maxSearchLength {
+		return nil, errors.New("q must be at most 200 characters")
+	}
+	query := textsearch.Parse(raw)
+	if query.Empty() {
+		return nil, errors.New("q has no searchable words")
+	}
+	return &query, nil
+}
+
+// Search runs a full-text search over open lost items and unreturned found
+// items, most relevant first. type=lost or type=found searches only one of
+// them. Each kind comes back as its own page, paged by offset; a cursor
+// continues one kind, so it needs type=lost or type=found.
+func Search(c *gin.Context) {
+	query, err := parseTextQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	searchType := c.DefaultQuery("type", "all")
+	if searchType != "all" && searchType != "lost" && searchType != "found" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be all, lost or found"})
+		return
+	}
+
+	page, ok := parsePage(c, false)
+	if !ok {
+		return
+	}
+	if page.Cursor != nil && searchType == "all" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor needs type=lost or type=found"})
+		return
+	}
+
+	// The kind not searched stays an empty page, so the response keeps its shape
+	response := gin.H{
+		"lostItems":  pagination.NewResult[models.LostItem](page, nil, nil),
+		"foundItems": pagination.NewResult[models.FoundItem](page, nil, nil),
+	}
+	if searchType != "found" {
+		filter := store.LostItemFilter{
+			Text:     query,
+			Statuses: []models.LostItemStatus{models.LostItemOpen, models.LostItemMatched},
+			Skip:     page.Skip(),
+			Limit:    page.Fetch(),
+		}
+		result, err := loadPage(page, nil,
+			func() ([]models.LostItem, error) { return store.LostItems.List(context.Background(), filter) },
+			func() (int64, error) { return store.LostItems.Count(context.Background(), filter) },
+		)
+		if err != nil {
+			log.Println("Search lost items:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search items"})
+			return
+		}
+		response["lostItems"] = result
+	}
+
+	if searchType != "lost" {
+		notFound := false
+		filter := store.FoundItemFilter{
+			Text:  query,
+			Found: &notFound,
+			Skip:  page.Skip(),
+			Limit: page.Fetch(),
+		}
+		result, err := loadPage(page, nil,
+			func() ([]models.FoundItem, error) { return store.FoundItems.List(context.Background(), filter) },
+			func() (int64, error) { return store.FoundItems.Count(context.Background(), filter) },
+		)
+		if err != nil {
+			log.Println("Search found items:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search items"})
+			return
+		}
+		response["foundItems"] = result
+	}
+
+	c.JSON(http.StatusOK, response)
+}
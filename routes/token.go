@@ -0,0 +1,192 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+	"lostfound-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// issueTokens creates a refresh token in family (a new family, i.e. a new
+// session, when family is zero) and an access token for the same session.
+// It returns the response fields the client needs.
+func issueTokens(user *models.User, family primitive.ObjectID) (gin.H, error) {
+	if family.IsZero() {
+		family = primitive.NewObjectID()
+	}
+	refresh, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	record := models.RefreshToken{
+		User:      user.ID,
+		Family:    family,
+		TokenHash: utils.HashToken(refresh),
+		ExpiresAt: now.Add(utils.RefreshTokenTTL),
+		CreatedAt: now,
+	}
+	if err := store.RefreshTokens.Insert(context.Background(), &record); err != nil {
+		return nil, err
+	}
+	return sessionTokens(user, family, refresh)
+}
+
+// sessionTokens signs a new access token and pairs it with refresh.
+func sessionTokens(user *models.User, family primitive.ObjectID, refresh string) (gin.H, error) {
+	access, err := utils.GenerateJWT(user.ID.Hex(), family.Hex(), string(user.CurrentRole()))
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"token":        access,
+		"refreshToken": refresh,
+		"expiresIn":    int(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new
+// refresh token. Each refresh token works once; presenting one that was
+// already exchanged revokes the whole session, since either the client or
+// an attacker is holding a stolen copy.
+func RefreshToken(c *gin.Context) {
+	var request struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	current, err := store.RefreshTokens.FindByHash(context.Background(), utils.HashToken(request.RefreshToken))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	now := time.Now()
+	if !current.RevokedAt.IsZero() || !now.Before(current.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token expired or revoked"})
+		return
+	}
+
+	// Re-read the user so the new access token carries their current role,
+	// and so a suspension ends the session at its next refresh
+	user, err := store.Users.FindByID(context.Background(), current.User)
+	if errors.Is(err, store.ErrNotFound) {
+		revokeSession(current.Family)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+	if user.Suspension.Active(now) {
+		revokeSession(current.Family)
+		suspendedResponse(c, user.Suspension)
+		return
+	}
+
+	refresh, err := utils.RandomToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+	next := models.RefreshToken{
+		User:      current.User,
+		Family:    current.Family,
+		TokenHash: utils.HashToken(refresh),
+		ExpiresAt: now.Add(utils.RefreshTokenTTL),
+		CreatedAt: now,
+	}
+	err = store.RefreshTokens.Rotate(context.Background(), current.ID, &next)
+	if errors.Is(err, store.ErrNotFound) {
+		log.Println("Refresh token reuse detected for user", current.User.Hex())
+		revokeSession(current.Family)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has already been used"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	tokens, err := sessionTokens(user, current.Family, refresh)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout ends the session of the access token used to call it: its refresh
+// tokens stop working and its access tokens are rejected from now on.
+func Logout(c *gin.Context) {
+	family, err := primitive.ObjectIDFromHex(c.GetString("sessionID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session"})
+		return
+	}
+	if err := revokeSession(family); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// revokeSession revokes every refresh token of the family, blocks its
+// access tokens until the last of them would have expired and closes its
+// notification streams.
+func revokeSession(family primitive.ObjectID) error {
+	now := time.Now()
+	if err := store.RefreshTokens.RevokeFamily(context.Background(), family, now); err != nil {
+		log.Println("RevokeFamily error:", err)
+		return err
+	}
+	notifications.CloseSession(family.Hex())
+	err := store.RevokedTokens.Revoke(context.Background(), &models.RevokedToken{
+		Family:    family,
+		ExpiresAt: now.Add(utils.AccessTokenTTL),
+	})
+	if err != nil {
+		log.Println("Revoke session error:", err)
+	}
+	return err
+}
+
+// revokeUserSessions ends every session of the user, e.g. after their
+// password changed.
+func revokeUserSessions(userID primitive.ObjectID) error {
+	now := time.Now()
+	families, err := store.RefreshTokens.RevokeUser(context.Background(), userID, now)
+	if err != nil {
+		log.Println("RevokeUser error:", err)
+		return err
+	}
+	for _, family := range families {
+		notifications.CloseSession(family.Hex())
+		err := store.RevokedTokens.Revoke(context.Background(), &models.RevokedToken{
+			Family:    family,
+			ExpiresAt: now.Add(utils.AccessTokenTTL),
+		})
+		if err != nil {
+			log.Println("Revoke session error:", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRevokingSessionsClosesStreams(t *testing.T) {
+	useMemory(t)
+	user := primitive.NewObjectID()
+	families := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	for _, family := range families {
+		err := store.RefreshTokens.Insert(context.Background(), &models.RefreshToken{
+			User: user, Family: family, TokenHash: family.Hex(), ExpiresAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	loggedOut := notifications.Subscribe(user.Hex(), families[0].Hex())
+	other := notifications.Subscribe(user.Hex(), families[1].Hex())
+	defer loggedOut.Close()
+	defer other.Close()
+
+	if err := revokeSession(families[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-loggedOut.Events(); ok {
+		t.Error("stream of a logged out session still open")
+	}
+	select {
+	case <-other.Events():
+		t.Fatal("logging out one session closed the user's other stream")
+	default:
+	}
+
+	if err := revokeUserSessions(user); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-other.Events(); ok {
+		t.Error("stream still open after every session of the user was revoked")
+	}
+}
@@ -0,0 +1,158 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"lostfound-backend/mailer"
+	"lostfound-backend/models"
+	"lostfound-backend/store"
+	"lostfound-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// emailVerificationTTL is how long a verification link stays valid.
+const emailVerificationTTL = 24 * time.Hour
+
+// VerifyEmail confirms an address with a token from a verification email.
+// For a changed address this is when the change takes effect.
+func VerifyEmail(c *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	token, err := store.OneTimeTokens.Consume(context.Background(),
+		models.TokenEmailVerification, utils.HashToken(request.Token), time.Now())
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	// Someone else may have registered the address since the link was sent
+	other, err := store.Users.FindByEmail(context.Background(), token.Email)
+	if err == nil && other.ID != token.User {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+		return
+	}
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	err = store.Users.VerifyEmail(context.Background(), token.User, token.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+		return
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	user, err := store.Users.FindByID(context.Background(), token.User)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+	user.Password = ""
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+		"user":    user,
+	})
+}
+
+// ResendVerification mails a new verification link for the pending address,
+// or for the current one if it is not verified yet. Earlier links stop
+// working.
+func ResendVerification(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	user, err := store.Users.FindByID(context.Background(), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	email := user.PendingEmail
+	if email == "" {
+		if user.EmailVerified {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email is already verified"})
+			return
+		}
+		email = user.Email
+	}
+
+	if err := sendVerificationEmail(user, email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
+}
+
+// RequireVerifiedEmail rejects users whose email is not verified yet. When
+// enabled is false it lets every request through, so deployments can turn
+// the restriction on without changing the routes. It must run after
+// AuthMiddleware.
+func RequireVerifiedEmail(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		userID, ok := currentUser(c)
+		if !ok {
+			c.Abort()
+			return
+		}
+		user, err := store.Users.FindByID(context.Background(), userID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+		if !user.EmailVerified {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Verify your email address before posting"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// sendVerificationEmail mails user a link that confirms email, replacing
+// any link sent earlier.
+func sendVerificationEmail(user *models.User, email string) error {
+	token, err := issueOneTimeToken(user.ID, models.TokenEmailVerification, email, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	sendMail(mailer.Message{
+		To:      email,
+		Subject: "Confirm your email address",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Please confirm that this is your email address by opening this link within the next 24 hours:\n\n" +
+			appLink("/verify-email", token) + "\n\n" +
+			"If you did not sign up or change your email, you can ignore this email.\n",
+	})
+	return nil
+}
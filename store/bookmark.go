@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BookmarkFilter selects a page of a user's bookmarks, newest first.
+type BookmarkFilter struct {
+	User primitive.ObjectID
+	// After continues the listing after the bookmark the cursor marks.
+	After *pagination.Cursor
+	Limit int64
+}
+
+func (f BookmarkFilter) query() bson.M {
+	filter := bson.M{"user": f.User}
+	keysetAfter(filter, "createdAt", f.After)
+	return filter
+}
+
+type BookmarkStore interface {
+	Insert(ctx context.Context, bookmark *models.Bookmark) error
+	// ListWithLostItems returns the user's bookmarks with the bookmarked
+	// lost item document embedded under "lostItem".
+	ListWithLostItems(ctx context.Context, filter BookmarkFilter) ([]bson.M, error)
+	// Count returns how many bookmarks the user has.
+	Count(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// DeleteOwned removes the bookmark only if it belongs to userID.
+	DeleteOwned(ctx context.Context, id, userID primitive.ObjectID) error
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClaimFilter narrows a claim listing. Zero fields are ignored.
+type ClaimFilter struct {
+	FoundItem primitive.ObjectID
+	Claimant  primitive.ObjectID
+	Status    models.ClaimStatus
+	// After continues the listing after the claim the cursor marks.
+	After *pagination.Cursor
+	Limit int64
+}
+
+func (f ClaimFilter) query() bson.M {
+	filter := bson.M{}
+	if !f.FoundItem.IsZero() {
+		filter["foundItem"] = f.FoundItem
+	}
+	if !f.Claimant.IsZero() {
+		filter["claimant"] = f.Claimant
+	}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	keysetAfter(filter, "createdAt", f.After)
+	return filter
+}
+
+func (f ClaimFilter) matches(claim *models.Claim) bool {
+	if !f.FoundItem.IsZero() && claim.FoundItem != f.FoundItem {
+		return false
+	}
+	if !f.Claimant.IsZero() && claim.Claimant != f.Claimant {
+		return false
+	}
+	if f.Status != "" && claim.Status != f.Status {
+		return false
+	}
+	return isAfter(claim.CreatedAt, claim.ID, f.After)
+}
+
+// ClaimStore writes are conditional on the claim still being pending, so
+// two racing decisions cannot both succeed; the loser gets ErrNotFound.
+type ClaimStore interface {
+	Insert(ctx context.Context, claim *models.Claim) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Claim, error)
+	// List returns matching claims, newest first.
+	List(ctx context.Context, filter ClaimFilter) ([]models.Claim, error)
+	// Count returns how many claims match filter, ignoring its cursor and
+	// limit.
+	Count(ctx context.Context, filter ClaimFilter) (int64, error)
+	AddQuestion(ctx context.Context, id primitive.ObjectID, q models.VerificationQuestion) error
+	AnswerQuestion(ctx context.Context, id, questionID primitive.ObjectID, answer string) error
+	// Decide moves a pending claim to status, recording the note. Only one
+	// claim per found item can be approved; approving a second one fails
+	// with a duplicate key error.
+	Decide(ctx context.Context, id primitive.ObjectID, status models.ClaimStatus, note string) error
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ConversationFilter selects a page of the conversations a user takes part
+// in, newest first. They page by when they were started rather than by
+// activity, which moves a conversation between pages as messages arrive.
+type ConversationFilter struct {
+	User primitive.ObjectID
+	// After continues the listing after the conversation the cursor marks.
+	After *pagination.Cursor
+	Limit int64
+}
+
+func (f ConversationFilter) query() bson.M {
+	filter := bson.M{"$or": bson.A{bson.M{"owner": f.User}, bson.M{"finder": f.User}}}
+	keysetAfter(filter, "createdAt", f.After)
+	return filter
+}
+
+// matches is the in-memory equivalent of query.
+func (f ConversationFilter) matches(conv *models.Conversation) bool {
+	return conv.HasParticipant(f.User) && isAfter(conv.CreatedAt, conv.ID, f.After)
+}
+
+type ConversationStore interface {
+	// FindOrCreate loads the conversation for conv's item pair into conv,
+	// inserting conv first if there is none yet. created reports whether
+	// it was inserted.
+	FindOrCreate(ctx context.Context, conv *models.Conversation) (created bool, err error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Conversation, error)
+	// ListForUser returns the conversations filter.User takes part in,
+	// newest first.
+	ListForUser(ctx context.Context, filter ConversationFilter) ([]models.Conversation, error)
+	// CountForUser returns how many conversations userID takes part in.
+	CountForUser(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// CountUnread counts the messages sent to userID after they last read
+	// the conversation, across all the conversations they take part in.
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// Touch records a new message sent at at.
+	Touch(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// MarkRead records that userID has read the conversation up to at. It
+	// returns ErrNotFound unless userID is a participant.
+	MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) error
+}
+
+// MessageFilter selects a page of one conversation's messages, newest first.
+type MessageFilter struct {
+	Conversation primitive.ObjectID
+	// After continues the listing after the message the cursor marks.
+	After *pagination.Cursor
+	Limit int64
+}
+
+func (f MessageFilter) query() bson.M {
+	filter := bson.M{"conversation": f.Conversation}
+	keysetAfter(filter, "createdAt", f.After)
+	return filter
+}
+
+type MessageStore interface {
+	Insert(ctx context.Context, message *models.Message) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Message, error)
+	List(ctx context.Context, filter MessageFilter) ([]models.Message, error)
+	// Count returns how many messages the conversation has.
+	Count(ctx context.Context, conversationID primitive.ObjectID) (int64, error)
+	// CountUnread counts the messages in a conversation sent to userID
+	// after since.
+	CountUnread(ctx context.Context, conversationID, userID primitive.ObjectID, since time.Time) (int64, error)
+	// SetHidden hides the message's body, or shows it again when hidden is nil.
+	SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"lostfound-backend/models"
+)
+
+// bsonField returns the name a field of T is stored under, read from its bson
+// tag. Nested fields are separated by dots, e.g. "Images.Hash" gives
+// "images.hash". It panics when the field does not exist, so the package
+// variables below fail at startup rather than letting a query quietly match
+// nothing after a model changes.
+func bsonField[T any](path string) string {
+	t := reflect.TypeFor[T]()
+	var names []string
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			panic(fmt.Sprintf("store: %s has no field %s", reflect.TypeFor[T](), path))
+		}
+		stored, _, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if stored == "" || stored == "-" {
+			panic(fmt.Sprintf("store: field %s of %s is not stored", path, reflect.TypeFor[T]()))
+		}
+		names = append(names, stored)
+		t = field.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// Stored names of the lost item fields queried by the stores.
+var (
+	lostItemName           = bsonField[models.LostItem]("Name")
+	lostItemDescription    = bsonField[models.LostItem]("Description")
+	lostItemCategory       = bsonField[models.LostItem]("Category")
+	lostItemImages         = bsonField[models.LostItem]("Images")
+	lostItemImagesVersion  = bsonField[models.LostItem]("ImagesVersion")
+	lostItemImageURL       = bsonField[models.LostItem]("ImageURL")
+	lostItemImageMedium    = bsonField[models.LostItem]("ImageMedium")
+	lostItemImageThumbnail = bsonField[models.LostItem]("ImageThumbnail")
+	lostItemDistrict       = bsonField[models.LostItem]("District")
+	lostItemState          = bsonField[models.LostItem]("State")
+	lostItemLocations      = bsonField[models.LostItem]("Locations")
+	lostItemPosition       = bsonField[models.LostItem]("Position")
+	lostItemDateLost       = bsonField[models.LostItem]("DateLost")
+	lostItemStatus         = bsonField[models.LostItem]("Status")
+	lostItemStatusHistory  = bsonField[models.LostItem]("StatusHistory")
+	lostItemCreatedBy      = bsonField[models.LostItem]("CreatedBy")
+	lostItemCreatedAt      = bsonField[models.LostItem]("CreatedAt")
+	lostItemUpdatedAt      = bsonField[models.LostItem]("UpdatedAt")
+)
+
+// Stored names of the found item fields queried by the stores.
+var (
+	foundItemLostItem       = bsonField[models.FoundItem]("LostItem")
+	foundItemPosition       = bsonField[models.FoundItem]("Position")
+	foundItemLostPerson     = bsonField[models.FoundItem]("LostPerson")
+	foundItemFoundPerson    = bsonField[models.FoundItem]("FoundPerson")
+	foundItemPhone          = bsonField[models.FoundItem]("FoundPersonPhone")
+	foundItemLocationFound  = bsonField[models.FoundItem]("LocationFound")
+	foundItemDescription    = bsonField[models.FoundItem]("Description")
+	foundItemDateFound      = bsonField[models.FoundItem]("DateFound")
+	foundItemName           = bsonField[models.FoundItem]("Name")
+	foundItemCategory       = bsonField[models.FoundItem]("Category")
+	foundItemDistrict       = bsonField[models.FoundItem]("District")
+	foundItemState          = bsonField[models.FoundItem]("State")
+	foundItemFound          = bsonField[models.FoundItem]("Found")
+	foundItemImages         = bsonField[models.FoundItem]("Images")
+	foundItemImagesVersion  = bsonField[models.FoundItem]("ImagesVersion")
+	foundItemImage          = bsonField[models.FoundItem]("Image")
+	foundItemImageMedium    = bsonField[models.FoundItem]("ImageMedium")
+	foundItemImageThumbnail = bsonField[models.FoundItem]("ImageThumbnail")
+	foundItemImageHash      = bsonField[models.FoundItem]("Images.Hash")
+	foundItemCreatedAt      = bsonField[models.FoundItem]("CreatedAt")
+	foundItemUpdatedAt      = bsonField[models.FoundItem]("UpdatedAt")
+)
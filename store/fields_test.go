@@ -0,0 +1,78 @@
+package store
+
+import (
+	"testing"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBSONField(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{bsonField[models.LostItem]("CreatedBy"), "createdBy"},
+		{bsonField[models.LostItem]("ImageURL"), "image"},
+		{bsonField[models.FoundItem]("FoundPerson"), "foundPerson"},
+		{bsonField[models.FoundItem]("Images.Hash"), "images.hash"},
+		{bsonField[models.FoundItem]("Position.Coordinates"), "position.coordinates"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("bsonField() = %q, want %q", tt.got, tt.want)
+		}
+	}
+
+	for _, path := range []string{"Owner", "Distance", "Images.Nope"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("bsonField(%q) did not panic", path)
+				}
+			}()
+			bsonField[models.FoundItem](path)
+		}()
+	}
+}
+
+// TestOwnerFields pins the ownership checks to the names the owner fields
+// are stored under since migration 2. Before it, lost items kept their owner
+// in "user" and some found items in "foundBy", and queries on the other name
+// silently matched nothing.
+func TestOwnerFields(t *testing.T) {
+	owner := primitive.NewObjectID()
+
+	lost, err := bson.Marshal(models.LostItem{CreatedBy: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !matchQuery(t, lost, LostItemFilter{CreatedBy: owner}.query()) {
+		t.Errorf("owner filter %v does not select a stored lost item", LostItemFilter{CreatedBy: owner}.query())
+	}
+	found, err := bson.Marshal(models.FoundItem{FoundPerson: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !matchQuery(t, found, FoundItemFilter{FoundPerson: owner}.query()) {
+		t.Errorf("finder filter %v does not select a stored found item", FoundItemFilter{FoundPerson: owner}.query())
+	}
+
+	// Documents still under the old names need the migration
+	for _, legacy := range []struct {
+		doc   bson.M
+		query bson.M
+	}{
+		{bson.M{"user": owner}, LostItemFilter{CreatedBy: owner}.query()},
+		{bson.M{"foundBy": owner}, FoundItemFilter{FoundPerson: owner}.query()},
+	} {
+		raw, err := bson.Marshal(legacy.doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matchQuery(t, raw, legacy.query) {
+			t.Errorf("%v selects the unmigrated document %v", legacy.query, legacy.doc)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The tests below check that every in-memory filter selects exactly what
+// its Mongo query does. Text and Near are left out: they depend on
+// MongoDB's text and geo indexes.
+
+var (
+	day   = 24 * time.Hour
+	epoch = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	alice = primitive.NewObjectID()
+	bob   = primitive.NewObjectID()
+	hide  = &models.ModerationAction{By: primitive.NewObjectID(), At: epoch, Reason: "spam"}
+	yes   = true
+	no    = false
+)
+
+func lostItemFixtures() []models.LostItem {
+	return []models.LostItem{
+		{Name: "Black wallet", Category: "Wallets", District: "Colombo", State: "Western", ImageURL: "/a.jpg", CreatedBy: alice, DateLost: epoch, CreatedAt: epoch},
+		{Name: "Blue phone", Category: "Phones", District: "Gampaha", State: "western", Status: models.LostItemMatched, Hidden: hide, CreatedBy: alice, DateLost: epoch.Add(day), CreatedAt: epoch.Add(time.Hour)},
+		{Name: "Car keys", Category: "keys", District: "Kandy", State: "Central", ImageURL: "/k.jpg", Status: models.LostItemRecovered, CreatedBy: bob, DateLost: epoch.Add(2 * day), CreatedAt: epoch.Add(time.Hour)},
+		{Name: "Red wallet", Category: "wallets", District: "Galle", State: "Southern", Status: models.LostItemOpen, CreatedBy: bob, DateLost: epoch.Add(3 * day), CreatedAt: epoch.Add(2 * time.Hour)},
+		{Name: "Umbrella", Category: "A+B", District: "Colombo", Status: models.LostItemClosed, Hidden: hide, CreatedBy: alice, DateLost: epoch.Add(4 * day), CreatedAt: epoch.Add(3 * time.Hour)},
+		{Name: "Wallet (brown)", Category: "Wallets", District: "Colombo 07", State: "Western", ImageURL: "", Status: models.LostItemExpired, CreatedBy: bob, DateLost: epoch.Add(5 * day), CreatedAt: epoch.Add(4 * time.Hour)},
+	}
+}
+
+func foundItemFixtures(lostItem primitive.ObjectID) []models.FoundItem {
+	hashed := []models.Image{{ID: primitive.NewObjectID(), URL: "/h.jpg", Primary: true, Hash: "00ff00ff00ff00ff"}}
+	unhashed := []models.Image{{ID: primitive.NewObjectID(), URL: "/u.jpg", Primary: true}}
+	return []models.FoundItem{
+		{Name: "Wallet", Category: "Wallets", State: "Western", Image: "/h.jpg", Images: hashed, FoundPerson: alice, DateFound: epoch, CreatedAt: epoch},
+		{Name: "Phone", Category: "phones", State: "western", Image: "/u.jpg", Images: unhashed, FoundPerson: alice, LostItem: lostItem, LostPerson: bob, Found: true, DateFound: epoch.Add(day), CreatedAt: epoch.Add(time.Hour)},
+		{Name: "Keys", Category: "Keys", FoundPerson: bob, Hidden: hide, DateFound: epoch.Add(2 * day), CreatedAt: epoch.Add(time.Hour)},
+		{Name: "Bag", FoundPerson: bob, Image: "/h.jpg", Images: hashed, DateFound: epoch.Add(3 * day), CreatedAt: epoch.Add(2 * time.Hour)},
+		{Name: "Watch", Category: "WALLETS", State: "Central", FoundPerson: alice, Hidden: hide, Images: hashed, Image: "/h.jpg", DateFound: epoch.Add(4 * day), CreatedAt: epoch.Add(3 * time.Hour)},
+	}
+}
+
+// mongoSelect returns the ids of the documents of a memory collection that
+// query selects, newest first.
+func mongoSelect(t *testing.T, m *Memory, collection string, query bson.M) []primitive.ObjectID {
+	t.Helper()
+	type doc struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		CreatedAt time.Time          `bson:"createdAt"`
+	}
+	var docs []doc
+	for _, raw := range m.collection(collection).all() {
+		if !matchQuery(t, raw, query) {
+			continue
+		}
+		var d doc
+		if err := bson.Unmarshal(raw, &d); err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, d)
+	}
+	sortNewestFirst(docs, func(d *doc) (time.Time, primitive.ObjectID) { return d.CreatedAt, d.ID })
+	ids := []primitive.ObjectID{}
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+func cursorAt(createdAt time.Time, id primitive.ObjectID) *pagination.Cursor {
+	cursor := pagination.After(createdAt, id)
+	return &cursor
+}
+
+func idsOf[T any](items []T, id func(*T) primitive.ObjectID) []primitive.ObjectID {
+	ids := []primitive.ObjectID{}
+	for i := range items {
+		ids = append(ids, id(&items[i]))
+	}
+	return ids
+}
+
+func TestLostItemFilter(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	items := lostItemFixtures()
+	for i := range items {
+		if err := m.LostItems().Insert(ctx, &items[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// items[1] and items[2] share a createdAt, so the cursor has to break
+	// the tie on _id
+	tie := cursorAt(items[2].CreatedAt, items[2].ID)
+	if items[1].ID.Hex() > items[2].ID.Hex() {
+		tie = cursorAt(items[1].CreatedAt, items[1].ID)
+	}
+
+	tests := []struct {
+		name   string
+		filter LostItemFilter
+	}{
+		{"everything visible", LostItemFilter{}},
+		{"all", LostItemFilter{Visibility: AllItems}},
+		{"hidden", LostItemFilter{Visibility: HiddenItems}},
+		{"owner", LostItemFilter{CreatedBy: alice}},
+		{"owner including hidden", LostItemFilter{CreatedBy: alice, Visibility: AllItems}},
+		{"other owner", LostItemFilter{CreatedBy: bob, Visibility: AllItems}},
+		{"unknown owner", LostItemFilter{CreatedBy: primitive.NewObjectID()}},
+		{"category", LostItemFilter{Categories: []string{"wallets"}}},
+		{"categories", LostItemFilter{Categories: []string{"WALLETS", "phones"}, Visibility: AllItems}},
+		{"category with regex characters", LostItemFilter{Categories: []string{"a+b"}, Visibility: AllItems}},
+		{"state", LostItemFilter{State: "WESTERN", Visibility: AllItems}},
+		{"state is not a pattern", LostItemFilter{State: "West"}},
+		{"name substring", LostItemFilter{Name: "WALLET"}},
+		{"name with regex characters", LostItemFilter{Name: "(brown)"}},
+		{"district substring", LostItemFilter{District: "colombo"}},
+		{"district is not a pattern", LostItemFilter{District: "^colombo$"}},
+		{"with image", LostItemFilter{HasImage: &yes}},
+		{"without image", LostItemFilter{HasImage: &no, Visibility: AllItems}},
+		{"open", LostItemFilter{Statuses: []models.LostItemStatus{models.LostItemOpen}}},
+		{"matched or recovered", LostItemFilter{Statuses: []models.LostItemStatus{models.LostItemMatched, models.LostItemRecovered}, Visibility: AllItems}},
+		{"lost after", LostItemFilter{LostAfter: epoch.Add(2 * day)}},
+		{"lost between", LostItemFilter{LostAfter: epoch.Add(day), LostBefore: epoch.Add(3 * day), Visibility: AllItems}},
+		{"after newest", LostItemFilter{After: cursorAt(items[5].CreatedAt, items[5].ID)}},
+		{"after tie", LostItemFilter{After: tie, Visibility: AllItems}},
+		{"owner after cursor", LostItemFilter{CreatedBy: alice, After: cursorAt(items[4].CreatedAt, items[4].ID), Visibility: AllItems}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.LostItems().List(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := mongoSelect(t, m, "lostitems", tt.filter.query())
+			if ids := idsOf(got, func(item *models.LostItem) primitive.ObjectID { return item.ID }); !slices.Equal(ids, want) {
+				t.Errorf("memory store listed %v, Mongo query selects %v", ids, want)
+			}
+			n, err := m.LostItems().Count(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.filter.After = nil
+			if want := len(mongoSelect(t, m, "lostitems", tt.filter.query())); n != int64(want) {
+				t.Errorf("Count() = %d, Mongo query counts %d", n, want)
+			}
+		})
+	}
+}
+
+func TestFoundItemFilter(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	lostItem := primitive.NewObjectID()
+	items := foundItemFixtures(lostItem)
+	for i := range items {
+		if err := m.FoundItems().Insert(ctx, &items[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter FoundItemFilter
+	}{
+		{"everything visible", FoundItemFilter{}},
+		{"all", FoundItemFilter{Visibility: AllItems}},
+		{"hidden", FoundItemFilter{Visibility: HiddenItems}},
+		{"finder", FoundItemFilter{FoundPerson: alice}},
+		{"finder including hidden", FoundItemFilter{FoundPerson: bob, Visibility: AllItems}},
+		{"unknown finder", FoundItemFilter{FoundPerson: primitive.NewObjectID()}},
+		{"lost item", FoundItemFilter{LostItem: lostItem}},
+		{"lost person", FoundItemFilter{LostPerson: bob}},
+		{"returned", FoundItemFilter{Found: &yes}},
+		{"not returned", FoundItemFilter{Found: &no, Visibility: AllItems}},
+		{"category", FoundItemFilter{Categories: []string{"wallets"}, Visibility: AllItems}},
+		{"state", FoundItemFilter{State: "Western"}},
+		{"with image", FoundItemFilter{HasImage: &yes, Visibility: AllItems}},
+		{"without image", FoundItemFilter{HasImage: &no, Visibility: AllItems}},
+		{"hashed images", FoundItemFilter{HashedImages: true}},
+		{"hashed images including hidden", FoundItemFilter{HashedImages: true, Visibility: AllItems}},
+		{"found window", FoundItemFilter{FoundAfter: epoch.Add(day), FoundBefore: epoch.Add(3 * day), Visibility: AllItems}},
+		{"after cursor", FoundItemFilter{After: cursorAt(items[3].CreatedAt, items[3].ID)}},
+		{"finder after cursor", FoundItemFilter{FoundPerson: alice, After: cursorAt(items[4].CreatedAt, items[4].ID), Visibility: AllItems}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.FoundItems().List(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := mongoSelect(t, m, "founditems", tt.filter.query())
+			if ids := idsOf(got, func(item *models.FoundItem) primitive.ObjectID { return item.ID }); !slices.Equal(ids, want) {
+				t.Errorf("memory store listed %v, Mongo query selects %v", ids, want)
+			}
+		})
+	}
+}
+
+// agree checks that matches and query pick out the same documents.
+func agree[T any](t *testing.T, docs []T, query bson.M, matches func(*T) bool) {
+	t.Helper()
+	for i := range docs {
+		raw, err := bson.Marshal(&docs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Decode the stored form, as the memory store does
+		var stored T
+		if err := bson.Unmarshal(raw, &stored); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := matches(&stored), matchQuery(t, raw, query); got != want {
+			t.Errorf("document %d: matches() = %v, Mongo query = %v", i, got, want)
+		}
+	}
+}
+
+func TestClaimFilter(t *testing.T) {
+	item, other := primitive.NewObjectID(), primitive.NewObjectID()
+	claims := []models.Claim{
+		{ID: primitive.NewObjectID(), FoundItem: item, Claimant: alice, Status: models.ClaimPending, CreatedAt: epoch},
+		{ID: primitive.NewObjectID(), FoundItem: item, Claimant: bob, Status: models.ClaimApproved, CreatedAt: epoch.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), FoundItem: other, Claimant: alice, Status: models.ClaimRejected, CreatedAt: epoch.Add(time.Hour)},
+	}
+	for name, filter := range map[string]ClaimFilter{
+		"everything":     {},
+		"found item":     {FoundItem: item},
+		"claimant":       {Claimant: alice},
+		"status":         {Status: models.ClaimApproved},
+		"claimant items": {Claimant: alice, FoundItem: other},
+		"after cursor":   {FoundItem: item, After: cursorAt(claims[1].CreatedAt, claims[1].ID)},
+		"after tie":      {After: cursorAt(claims[2].CreatedAt, claims[2].ID)},
+	} {
+		t.Run(name, func(t *testing.T) { agree(t, claims, filter.query(), filter.matches) })
+	}
+}
+
+func TestNotificationFilter(t *testing.T) {
+	notifications := []models.Notification{
+		{ID: primitive.NewObjectID(), User: alice, Read: true, CreatedAt: epoch},
+		{ID: primitive.NewObjectID(), User: alice, CreatedAt: epoch.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), User: bob, CreatedAt: epoch.Add(2 * time.Hour)},
+	}
+	for name, filter := range map[string]NotificationFilter{
+		"user":         {User: alice},
+		"unread":       {User: alice, UnreadOnly: true},
+		"other user":   {User: bob, UnreadOnly: true},
+		"after cursor": {User: alice, After: cursorAt(notifications[1].CreatedAt, notifications[1].ID)},
+		"since cursor": {User: alice, Since: cursorAt(notifications[0].CreatedAt, notifications[0].ID)},
+	} {
+		t.Run(name, func(t *testing.T) { agree(t, notifications, filter.query(), filter.matches) })
+	}
+}
+
+func TestConversationFilter(t *testing.T) {
+	carol := primitive.NewObjectID()
+	conversations := []models.Conversation{
+		{ID: primitive.NewObjectID(), Owner: alice, Finder: bob, CreatedAt: epoch},
+		{ID: primitive.NewObjectID(), Owner: bob, Finder: carol, CreatedAt: epoch.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Owner: carol, Finder: alice, CreatedAt: epoch.Add(2 * time.Hour)},
+	}
+	for name, filter := range map[string]ConversationFilter{
+		"owner or finder": {User: alice},
+		"both roles":      {User: bob},
+		"stranger":        {User: primitive.NewObjectID()},
+		"after cursor":    {User: alice, After: cursorAt(conversations[2].CreatedAt, conversations[2].ID)},
+	} {
+		t.Run(name, func(t *testing.T) { agree(t, conversations, filter.query(), filter.matches) })
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	lost, found := primitive.NewObjectID(), primitive.NewObjectID()
+	matches := []models.Match{
+		{ID: primitive.NewObjectID(), LostItem: lost, FoundItem: found, Score: 0.9},
+		{ID: primitive.NewObjectID(), LostItem: lost, FoundItem: primitive.NewObjectID(), Score: 0.5},
+		{ID: primitive.NewObjectID(), LostItem: primitive.NewObjectID(), FoundItem: found, Score: 0.4},
+	}
+	for name, filter := range map[string]MatchFilter{
+		"lost item":  {LostItem: lost},
+		"found item": {FoundItem: found},
+		"pair":       {LostItem: lost, FoundItem: found},
+	} {
+		t.Run(name, func(t *testing.T) { agree(t, matches, filter.query(), filter.matches) })
+	}
+}
+
+// TestMatchViewerFilter checks the condition applied to the other item of
+// each match once the Mongo store has looked it up into "item".
+func TestMatchViewerFilter(t *testing.T) {
+	type foundLookup struct {
+		Item []models.FoundItem `bson:"item"`
+	}
+	type lostLookup struct {
+		Item []models.LostItem `bson:"item"`
+	}
+	// $lookup leaves an empty array when the item is gone
+	found := []foundLookup{
+		{Item: []models.FoundItem{}},
+		{Item: []models.FoundItem{{FoundPerson: alice}}},
+		{Item: []models.FoundItem{{FoundPerson: alice, Hidden: hide}}},
+		{Item: []models.FoundItem{{FoundPerson: bob, Hidden: hide}}},
+	}
+	lost := []lostLookup{
+		{Item: []models.LostItem{}},
+		{Item: []models.LostItem{{CreatedBy: alice}}},
+		{Item: []models.LostItem{{CreatedBy: alice, Hidden: hide}}},
+		{Item: []models.LostItem{{CreatedBy: bob, Hidden: hide}}},
+	}
+	for name, viewer := range map[string]*MatchViewer{
+		"user":  {User: alice},
+		"staff": {User: bob, Staff: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			filter := MatchFilter{LostItem: primitive.NewObjectID(), Viewer: viewer}
+			agree(t, found, filter.viewerQuery(), func(doc *foundLookup) bool {
+				return len(doc.Item) > 0 && filter.visibleTo(doc.Item[0].Hidden, doc.Item[0].FoundPerson)
+			})
+			filter = MatchFilter{FoundItem: primitive.NewObjectID(), Viewer: viewer}
+			agree(t, lost, filter.viewerQuery(), func(doc *lostLookup) bool {
+				return len(doc.Item) > 0 && filter.visibleTo(doc.Item[0].Hidden, doc.Item[0].CreatedBy)
+			})
+		})
+	}
+}
+
+func TestReportAndAuditFilters(t *testing.T) {
+	target := primitive.NewObjectID()
+	reports := []models.Report{
+		{ID: primitive.NewObjectID(), TargetType: models.TargetLostItem, Target: target, Status: models.ReportOpen},
+		{ID: primitive.NewObjectID(), TargetType: models.TargetFoundItem, Target: primitive.NewObjectID(), Status: models.ReportOpen},
+		{ID: primitive.NewObjectID(), TargetType: models.TargetLostItem, Target: target, Status: models.ReportDismissed},
+	}
+	for name, filter := range map[string]ReportFilter{
+		"everything":  {},
+		"status":      {Status: models.ReportOpen},
+		"target type": {TargetType: models.TargetLostItem},
+		"target":      {Target: target, Status: models.ReportDismissed},
+	} {
+		t.Run("reports/"+name, func(t *testing.T) { agree(t, reports, filter.query(), filter.matches) })
+	}
+
+	entries := []models.AuditEntry{
+		{ID: primitive.NewObjectID(), Actor: alice, Target: target, CreatedAt: epoch},
+		{ID: primitive.NewObjectID(), Actor: bob, Target: target, CreatedAt: epoch.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Target: primitive.NewObjectID(), CreatedAt: epoch.Add(time.Hour)},
+	}
+	for name, filter := range map[string]AuditFilter{
+		"everything":   {},
+		"actor":        {Actor: alice},
+		"target":       {Target: target},
+		"after cursor": {After: cursorAt(entries[1].CreatedAt, entries[1].ID)},
+	} {
+		t.Run("audit/"+name, func(t *testing.T) { agree(t, entries, filter.query(), filter.matches) })
+	}
+}
@@ -0,0 +1,187 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FoundItemFilter narrows a found item listing. Zero fields are ignored.
+type FoundItemFilter struct {
+	LostItem    primitive.ObjectID
+	LostPerson  primitive.ObjectID
+	FoundPerson primitive.ObjectID
+	// Found filters on the returned flag when non-nil.
+	Found *bool
+	// Categories keeps only items in one of these categories, ignoring case.
+	Categories []string
+	State      string // case-insensitive, whole value
+	// HasImage filters on whether the item has an image when non-nil.
+	HasImage *bool
+	// Near limits results to a radius and sorts them by distance.
+	Near *GeoNear
+	// Text limits results to a full-text match and sorts them by relevance.
+	// It cannot be combined with Near.
+	Text *textsearch.Query
+	// FoundAfter and FoundBefore bound DateFound, inclusive.
+	FoundAfter  time.Time
+	FoundBefore time.Time
+	// HashedImages keeps only items with at least one hashed image.
+	HashedImages bool
+	Visibility   Visibility
+	// Sort orders listings without Near or Text.
+	Sort SortOrder
+	// After continues a listing in the default Sort after the item the
+	// cursor marks.
+	After *pagination.Cursor
+	Limit int64
+	Skip  int64
+}
+
+func (f FoundItemFilter) query() bson.M {
+	filter := bson.M{}
+	if !f.LostItem.IsZero() {
+		filter[foundItemLostItem] = f.LostItem
+	}
+	if !f.LostPerson.IsZero() {
+		filter[foundItemLostPerson] = f.LostPerson
+	}
+	if !f.FoundPerson.IsZero() {
+		filter[foundItemFoundPerson] = f.FoundPerson
+	}
+	if f.Found != nil {
+		filter[foundItemFound] = *f.Found
+	}
+	if len(f.Categories) > 0 {
+		filter[foundItemCategory] = equalsAny(f.Categories...)
+	}
+	if f.State != "" {
+		filter[foundItemState] = equalsAny(f.State)
+	}
+	if f.HasImage != nil {
+		filter[foundItemImage] = nonEmpty(*f.HasImage)
+	}
+	if dateFound := dateRange(f.FoundAfter, f.FoundBefore); dateFound != nil {
+		filter[foundItemDateFound] = dateFound
+	}
+	if f.Text != nil {
+		filter["$text"] = textSearch(f.Text)
+	}
+	if f.HashedImages {
+		filter[foundItemImageHash] = bson.M{"$exists": true}
+	}
+	f.Visibility.query(filter)
+	keysetAfter(filter, foundItemCreatedAt, f.After)
+	return filter
+}
+
+// FoundItemUpdate holds the fields of a partial update. Nil fields are left
+// unchanged. The returned flag, the lost item link and moderation are not
+// part of it; they change only through claims and moderators.
+type FoundItemUpdate struct {
+	Name             *string
+	Description      *string
+	Category         *string
+	District         *string
+	State            *string
+	LocationFound    *string
+	FoundPersonPhone *string
+	Position         *models.GeoPoint
+}
+
+// Empty reports whether the update would change nothing.
+func (u FoundItemUpdate) Empty() bool {
+	return u == FoundItemUpdate{}
+}
+
+func (u FoundItemUpdate) set(now time.Time) bson.M {
+	set := bson.M{foundItemUpdatedAt: now}
+	if u.Name != nil {
+		set[foundItemName] = *u.Name
+	}
+	if u.Description != nil {
+		set[foundItemDescription] = *u.Description
+	}
+	if u.Category != nil {
+		set[foundItemCategory] = *u.Category
+	}
+	if u.District != nil {
+		set[foundItemDistrict] = *u.District
+	}
+	if u.State != nil {
+		set[foundItemState] = *u.State
+	}
+	if u.LocationFound != nil {
+		set[foundItemLocationFound] = *u.LocationFound
+	}
+	if u.FoundPersonPhone != nil {
+		set[foundItemPhone] = *u.FoundPersonPhone
+	}
+	if u.Position != nil {
+		set[foundItemPosition] = u.Position
+	}
+	return set
+}
+
+// apply is the in-memory equivalent of set.
+func (u FoundItemUpdate) apply(item *models.FoundItem, now time.Time) {
+	if u.Name != nil {
+		item.Name = *u.Name
+	}
+	if u.Description != nil {
+		item.Description = *u.Description
+	}
+	if u.Category != nil {
+		item.Category = *u.Category
+	}
+	if u.District != nil {
+		item.District = *u.District
+	}
+	if u.State != nil {
+		item.State = *u.State
+	}
+	if u.LocationFound != nil {
+		item.LocationFound = *u.LocationFound
+	}
+	if u.FoundPersonPhone != nil {
+		item.FoundPersonPhone = *u.FoundPersonPhone
+	}
+	if u.Position != nil {
+		item.Position = u.Position
+	}
+	item.UpdatedAt = now
+}
+
+type FoundItemStore interface {
+	Insert(ctx context.Context, item *models.FoundItem) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.FoundItem, error)
+	List(ctx context.Context, filter FoundItemFilter) ([]models.FoundItem, error)
+	// Count returns how many items match filter, ignoring its cursor, limit
+	// and skip.
+	Count(ctx context.Context, filter FoundItemFilter) (int64, error)
+	// UpdateOwned applies a partial update to an item reported by owner,
+	// bumping UpdatedAt, and returns the updated item.
+	UpdateOwned(ctx context.Context, id, owner primitive.ObjectID, update FoundItemUpdate) (*models.FoundItem, error)
+	// SetFound sets the returned flag and, unless they are zero, the lost
+	// item it answered and who it was returned to.
+	SetFound(ctx context.Context, id primitive.ObjectID, found bool, lostItem, lostPerson primitive.ObjectID) error
+	// DeleteOwned removes the item only if it was reported by owner, and
+	// returns what was removed.
+	DeleteOwned(ctx context.Context, id, owner primitive.ObjectID) (*models.FoundItem, error)
+	// SetImages replaces the images of an item reported by owner, along with
+	// the single-image fields, and returns the updated item. It returns
+	// ErrConflict when the images changed since version.
+	SetImages(ctx context.Context, id, owner primitive.ObjectID, version int, images []models.Image) (*models.FoundItem, error)
+	// SetHidden hides the item from listings, or shows it again when hidden is nil.
+	SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error
+	// Delete removes the item whoever reported it.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Images returns the images of every item, hidden or not.
+	Images(ctx context.Context) ([]models.Image, error)
+}
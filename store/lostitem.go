@@ -0,0 +1,223 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+	"lostfound-backend/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LostItemFilter narrows a lost item listing. Zero fields are ignored.
+type LostItemFilter struct {
+	Name     string // case-insensitive substring
+	District string // case-insensitive substring
+	// Categories keeps only items in one of these categories, ignoring case.
+	Categories []string
+	State      string // case-insensitive, whole value
+	// HasImage filters on whether the item has an image when non-nil.
+	HasImage  *bool
+	CreatedBy primitive.ObjectID
+	// Statuses keeps only items in one of these statuses. Items stored
+	// without a status count as open.
+	Statuses []models.LostItemStatus
+	// Near limits results to a radius and sorts them by distance.
+	Near *GeoNear
+	// Text limits results to a full-text match and sorts them by relevance.
+	// It cannot be combined with Near.
+	Text *textsearch.Query
+	// LostAfter and LostBefore bound DateLost, inclusive.
+	LostAfter  time.Time
+	LostBefore time.Time
+	Visibility Visibility
+	// Sort orders listings without Near or Text.
+	Sort SortOrder
+	// After continues a listing in the default Sort after the item the
+	// cursor marks.
+	After *pagination.Cursor
+	Limit int64
+	Skip  int64
+}
+
+func (f LostItemFilter) query() bson.M {
+	filter := bson.M{}
+	if f.Name != "" {
+		filter[lostItemName] = containing(f.Name)
+	}
+	if f.District != "" {
+		filter[lostItemDistrict] = containing(f.District)
+	}
+	if len(f.Categories) > 0 {
+		filter[lostItemCategory] = equalsAny(f.Categories...)
+	}
+	if f.State != "" {
+		filter[lostItemState] = equalsAny(f.State)
+	}
+	if f.HasImage != nil {
+		filter[lostItemImageURL] = nonEmpty(*f.HasImage)
+	}
+	if !f.CreatedBy.IsZero() {
+		filter[lostItemCreatedBy] = f.CreatedBy
+	}
+	if len(f.Statuses) > 0 {
+		filter[lostItemStatus] = statusIn(f.Statuses...)
+	}
+	if dateLost := dateRange(f.LostAfter, f.LostBefore); dateLost != nil {
+		filter[lostItemDateLost] = dateLost
+	}
+	if f.Text != nil {
+		filter["$text"] = textSearch(f.Text)
+	}
+	f.Visibility.query(filter)
+	keysetAfter(filter, lostItemCreatedAt, f.After)
+	return filter
+}
+
+// statusIn matches any of statuses, and a missing status when open is one of them.
+func statusIn(statuses ...models.LostItemStatus) bson.M {
+	values := bson.A{}
+	for _, status := range statuses {
+		values = append(values, status)
+		if status == models.LostItemOpen {
+			values = append(values, nil)
+		}
+	}
+	return bson.M{"$in": values}
+}
+
+func hasStatus(item *models.LostItem, statuses []models.LostItemStatus) bool {
+	for _, status := range statuses {
+		if item.CurrentStatus() == status {
+			return true
+		}
+	}
+	return false
+}
+
+// LostItemUpdate holds the fields of a partial update. Nil fields are left unchanged.
+type LostItemUpdate struct {
+	Name        *string
+	Description *string
+	Category    *string
+	// Images also updates the single-image fields from the primary image.
+	// It replaces the images at ImagesVersion; when they have changed since,
+	// the update fails with ErrConflict
+	Images        *[]models.Image
+	ImagesVersion int
+	District      *string
+	State         *string
+	Locations     *[]string
+	Position      *models.GeoPoint
+	DateLost      *time.Time
+}
+
+// Empty reports whether the update would change nothing.
+func (u LostItemUpdate) Empty() bool {
+	return u == LostItemUpdate{}
+}
+
+func (u LostItemUpdate) set(now time.Time) bson.M {
+	set := bson.M{lostItemUpdatedAt: now}
+	if u.Name != nil {
+		set[lostItemName] = *u.Name
+	}
+	if u.Description != nil {
+		set[lostItemDescription] = *u.Description
+	}
+	if u.Category != nil {
+		set[lostItemCategory] = *u.Category
+	}
+	if u.Images != nil {
+		var item models.LostItem
+		item.SetImages(*u.Images)
+		set[lostItemImages] = item.Images
+		set[lostItemImageURL] = item.ImageURL
+		set[lostItemImageMedium] = item.ImageMedium
+		set[lostItemImageThumbnail] = item.ImageThumbnail
+		set[lostItemImagesVersion] = u.ImagesVersion + 1
+	}
+	if u.District != nil {
+		set[lostItemDistrict] = *u.District
+	}
+	if u.State != nil {
+		set[lostItemState] = *u.State
+	}
+	if u.Locations != nil {
+		set[lostItemLocations] = *u.Locations
+	}
+	if u.Position != nil {
+		set[lostItemPosition] = u.Position
+	}
+	if u.DateLost != nil {
+		set[lostItemDateLost] = *u.DateLost
+	}
+	return set
+}
+
+// apply is the in-memory equivalent of set.
+func (u LostItemUpdate) apply(item *models.LostItem, now time.Time) {
+	if u.Name != nil {
+		item.Name = *u.Name
+	}
+	if u.Description != nil {
+		item.Description = *u.Description
+	}
+	if u.Category != nil {
+		item.Category = *u.Category
+	}
+	if u.Images != nil {
+		item.SetImages(*u.Images)
+		item.ImagesVersion = u.ImagesVersion + 1
+	}
+	if u.District != nil {
+		item.District = *u.District
+	}
+	if u.State != nil {
+		item.State = *u.State
+	}
+	if u.Locations != nil {
+		item.Locations = *u.Locations
+	}
+	if u.Position != nil {
+		item.Position = u.Position
+	}
+	if u.DateLost != nil {
+		item.DateLost = *u.DateLost
+	}
+	item.UpdatedAt = now
+}
+
+type LostItemStore interface {
+	Insert(ctx context.Context, item *models.LostItem) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.LostItem, error)
+	List(ctx context.Context, filter LostItemFilter) ([]models.LostItem, error)
+	// Count returns how many items match filter, ignoring its cursor, limit
+	// and skip.
+	Count(ctx context.Context, filter LostItemFilter) (int64, error)
+	// Distinct returns the distinct string values stored under a field.
+	Distinct(ctx context.Context, field string) ([]string, error)
+	// UpdateOwned applies a partial update to an item created by owner,
+	// bumping UpdatedAt, and returns the updated item. It returns
+	// ErrConflict when the update has Images and they changed since
+	// ImagesVersion.
+	UpdateOwned(ctx context.Context, id, owner primitive.ObjectID, update LostItemUpdate) (*models.LostItem, error)
+	// SetStatus applies change if the item is still in change.From, appending
+	// it to the status history. Otherwise it returns ErrNotFound.
+	SetStatus(ctx context.Context, id primitive.ObjectID, change models.LostItemStatusChange) error
+	// ExpireStale moves open items lost before cutoff to expired and
+	// returns how many were changed.
+	ExpireStale(ctx context.Context, cutoff time.Time) (int64, error)
+	// DeleteOwned removes the item only if it was created by owner, and
+	// returns what was removed.
+	DeleteOwned(ctx context.Context, id, owner primitive.ObjectID) (*models.LostItem, error)
+	// SetHidden hides the item from listings, or shows it again when hidden is nil.
+	SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error
+	// Delete removes the item whoever created it.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Images returns the images of every item, hidden or not.
+	Images(ctx context.Context) ([]models.Image, error)
+}
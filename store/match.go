@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchFilter selects a page of the matches of one lost or found item,
+// best score first. Zero fields are ignored.
+type MatchFilter struct {
+	LostItem  primitive.ObjectID
+	FoundItem primitive.ObjectID
+	// Viewer, when set, keeps only the matches whose other item, the found
+	// item of a lost item's matches and the lost item of a found item's,
+	// still exists and is not hidden from the viewer. Pages and counts then
+	// cover exactly the matches the viewer can see.
+	Viewer *MatchViewer
+	Limit  int64
+	Skip   int64
+}
+
+// MatchViewer is who a match listing is for. Hidden items are shown to
+// staff and to the user who reported them.
+type MatchViewer struct {
+	User  primitive.ObjectID
+	Staff bool
+}
+
+// other names the collection of the items the matches are listed for, and
+// the match and owner fields that lead to them.
+func (f MatchFilter) other() (collection, matchField, ownerField string) {
+	if f.LostItem.IsZero() {
+		return "lostitems", "lostItem", lostItemCreatedBy
+	}
+	return "founditems", "foundItem", foundItemFoundPerson
+}
+
+// viewerQuery selects, after the other item was looked up into the "item"
+// array, the matches Viewer may see.
+func (f MatchFilter) viewerQuery() bson.M {
+	_, _, ownerField := f.other()
+	filter := bson.M{"item": bson.M{"$ne": bson.A{}}}
+	if !f.Viewer.Staff {
+		filter["$or"] = bson.A{
+			bson.M{"item.hidden": bson.M{"$exists": false}},
+			bson.M{"item." + ownerField: f.Viewer.User},
+		}
+	}
+	return filter
+}
+
+// visibleTo is the in-memory equivalent of viewerQuery, for an other item
+// that still exists and was reported by owner.
+func (f MatchFilter) visibleTo(hidden *models.ModerationAction, owner primitive.ObjectID) bool {
+	return f.Viewer.Staff || hidden == nil || owner == f.Viewer.User
+}
+
+func (f MatchFilter) query() bson.M {
+	filter := bson.M{}
+	if !f.LostItem.IsZero() {
+		filter["lostItem"] = f.LostItem
+	}
+	if !f.FoundItem.IsZero() {
+		filter["foundItem"] = f.FoundItem
+	}
+	return filter
+}
+
+// matches is the in-memory equivalent of query.
+func (f MatchFilter) matches(match *models.Match) bool {
+	return (f.LostItem.IsZero() || match.LostItem == f.LostItem) &&
+		(f.FoundItem.IsZero() || match.FoundItem == f.FoundItem)
+}
+
+type MatchStore interface {
+	// Upsert stores the match, replacing the score of an existing
+	// lostItem/foundItem pair instead of adding a second one.
+	Upsert(ctx context.Context, match *models.Match) error
+	// List returns matches best score first.
+	List(ctx context.Context, filter MatchFilter) ([]models.Match, error)
+	// Count returns how many matches filter selects, ignoring its limit
+	// and skip.
+	Count(ctx context.Context, filter MatchFilter) (int64, error)
+	// ListByLostItem and ListByFoundItem return all of an item's matches,
+	// best score first.
+	ListByLostItem(ctx context.Context, lostItemID primitive.ObjectID) ([]models.Match, error)
+	ListByFoundItem(ctx context.Context, foundItemID primitive.ObjectID) ([]models.Match, error)
+	DeleteByLostItem(ctx context.Context, lostItemID primitive.ObjectID) error
+	DeleteByFoundItem(ctx context.Context, foundItemID primitive.ObjectID) error
+}
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Memory is an in-process database backing the in-memory stores. Documents
+// are kept BSON-encoded, so a read returns exactly what a round trip through
+// MongoDB would (omitempty fields dropped, times truncated to milliseconds).
+type Memory struct {
+	mu          sync.RWMutex
+	collections map[string]*memCollection
+}
+
+func NewMemory() *Memory {
+	return &Memory{collections: map[string]*memCollection{}}
+}
+
+func (m *Memory) LostItems() LostItemStore         { return &memoryLostItems{m: m} }
+func (m *Memory) FoundItems() FoundItemStore       { return &memoryFoundItems{m: m} }
+func (m *Memory) Users() UserStore                 { return &memoryUsers{m: m} }
+func (m *Memory) Bookmarks() BookmarkStore         { return &memoryBookmarks{m: m} }
+func (m *Memory) Matches() MatchStore              { return &memoryMatches{m: m} }
+func (m *Memory) Claims() ClaimStore               { return &memoryClaims{m: m} }
+func (m *Memory) Notifications() NotificationStore { return &memoryNotifications{m: m} }
+func (m *Memory) Conversations() ConversationStore { return &memoryConversations{m: m} }
+func (m *Memory) Messages() MessageStore           { return &memoryMessages{m: m} }
+func (m *Memory) RefreshTokens() RefreshTokenStore { return &memoryRefreshTokens{m: m} }
+func (m *Memory) RevokedTokens() RevokedTokenStore { return &memoryRevokedTokens{m: m} }
+func (m *Memory) OneTimeTokens() OneTimeTokenStore { return &memoryOneTimeTokens{m: m} }
+func (m *Memory) Reports() ReportStore             { return &memoryReports{m: m} }
+func (m *Memory) AuditLog() AuditStore             { return &memoryAuditLog{m: m} }
+
+// collection returns the named collection, creating it on first use.
+// Callers must hold m.mu.
+func (m *Memory) collection(name string) *memCollection {
+	c, ok := m.collections[name]
+	if !ok {
+		c = &memCollection{name: name, docs: map[primitive.ObjectID]bson.Raw{}}
+		m.collections[name] = c
+	}
+	return c
+}
+
+// memCollection holds one collection's documents in insertion order, which
+// stands in for MongoDB's natural order.
+type memCollection struct {
+	name  string
+	docs  map[primitive.ObjectID]bson.Raw
+	order []primitive.ObjectID
+}
+
+// insert encodes doc, assigning an _id when it has none, and returns the id.
+func (c *memCollection) insert(doc interface{}) (primitive.ObjectID, error) {
+	d, err := toD(doc)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	var id primitive.ObjectID
+	if i := indexOfKey(d, "_id"); i >= 0 {
+		oid, ok := d[i].Value.(primitive.ObjectID)
+		if !ok {
+			return primitive.NilObjectID, fmt.Errorf("%s: _id must be an ObjectID, got %T", c.name, d[i].Value)
+		}
+		id = oid
+	} else {
+		id = primitive.NewObjectID()
+		d = append(bson.D{{Key: "_id", Value: id}}, d...)
+	}
+
+	if _, exists := c.docs[id]; exists {
+		return primitive.NilObjectID, duplicateKeyError(c.name, "_id_")
+	}
+
+	raw, err := bson.Marshal(d)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	c.docs[id] = raw
+	c.order = append(c.order, id)
+	return id, nil
+}
+
+func (c *memCollection) get(id primitive.ObjectID) (bson.Raw, bool) {
+	raw, ok := c.docs[id]
+	return raw, ok
+}
+
+// all returns every document in natural order.
+func (c *memCollection) all() []bson.Raw {
+	out := make([]bson.Raw, 0, len(c.order))
+	for _, id := range c.order {
+		out = append(out, c.docs[id])
+	}
+	return out
+}
+
+// set overlays the top-level keys of the encoded fields onto the stored
+// document, like a $set with the same value.
+func (c *memCollection) set(id primitive.ObjectID, fields interface{}) error {
+	raw, ok := c.docs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var d bson.D
+	if err := bson.Unmarshal(raw, &d); err != nil {
+		return err
+	}
+	update, err := toD(fields)
+	if err != nil {
+		return err
+	}
+	for _, e := range update {
+		if i := indexOfKey(d, e.Key); i >= 0 {
+			d[i].Value = e.Value
+		} else {
+			d = append(d, e)
+		}
+	}
+	out, err := bson.Marshal(d)
+	if err != nil {
+		return err
+	}
+	c.docs[id] = out
+	return nil
+}
+
+// replace swaps the stored document for doc, keeping its _id.
+func (c *memCollection) replace(id primitive.ObjectID, doc interface{}) error {
+	if _, ok := c.docs[id]; !ok {
+		return ErrNotFound
+	}
+	d, err := toD(doc)
+	if err != nil {
+		return err
+	}
+	if i := indexOfKey(d, "_id"); i >= 0 {
+		d = append(d[:i], d[i+1:]...)
+	}
+	d = append(bson.D{{Key: "_id", Value: id}}, d...)
+	raw, err := bson.Marshal(d)
+	if err != nil {
+		return err
+	}
+	c.docs[id] = raw
+	return nil
+}
+
+func (c *memCollection) remove(id primitive.ObjectID) bool {
+	if _, ok := c.docs[id]; !ok {
+		return false
+	}
+	delete(c.docs, id)
+	for i, oid := range c.order {
+		if oid == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// distinct mirrors MongoDB's distinct for string fields: array values are
+// unwound and anything that is not a string is skipped.
+func (c *memCollection) distinct(field string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	add := func(v bson.RawValue) {
+		if s, ok := v.StringValueOK(); ok && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	for _, raw := range c.all() {
+		v, err := raw.LookupErr(strings.Split(field, ".")...)
+		if err != nil {
+			continue
+		}
+		if arr, ok := v.ArrayOK(); ok {
+			values, _ := arr.Values()
+			for _, e := range values {
+				add(e)
+			}
+			continue
+		}
+		add(v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func toD(doc interface{}) (bson.D, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var d bson.D
+	if err := bson.Unmarshal(raw, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func indexOfKey(d bson.D, key string) int {
+	for i, e := range d {
+		if e.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// duplicateKeyError builds the same error the driver returns for an E11000
+// violation, so mongo.IsDuplicateKeyError works against either backend.
+func duplicateKeyError(collection, index string) error {
+	return mongo.WriteException{WriteErrors: []mongo.WriteError{{
+		Code:    11000,
+		Message: fmt.Sprintf("E11000 duplicate key error collection: %s index: %s", collection, index),
+	}}}
+}
+
+// page applies skip and limit to n results, returning the [start, end) window.
+func page(n int, skip, limit int64) (int, int) {
+	start := int(skip)
+	if start < 0 {
+		start = 0
+	}
+	if start > n {
+		start = n
+	}
+	end := n
+	if limit > 0 && start+int(limit) < end {
+		end = start + int(limit)
+	}
+	return start, end
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryBookmarks struct {
+	m *Memory
+}
+
+func (s *memoryBookmarks) coll() *memCollection { return s.m.collection("bookmarks") }
+
+func (s *memoryBookmarks) Insert(ctx context.Context, bookmark *models.Bookmark) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, raw := range s.coll().all() {
+		user, _ := raw.Lookup("user").ObjectIDOK()
+		lostItem, _ := raw.Lookup("lostItem").ObjectIDOK()
+		if user == bookmark.User && lostItem == bookmark.LostItem {
+			return duplicateKeyError("bookmarks", "user_lostItem")
+		}
+	}
+	id, err := s.coll().insert(bookmark)
+	if err != nil {
+		return err
+	}
+	bookmark.ID = id
+	return nil
+}
+
+// ListWithLostItems mirrors the $match/$sort/$limit/$lookup/$unwind pipeline
+// of the Mongo store: an unresolved lost item drops the "lostItem" key entirely.
+func (s *memoryBookmarks) ListWithLostItems(ctx context.Context, filter BookmarkFilter) ([]bson.M, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var bookmarks []models.Bookmark
+	for _, raw := range s.coll().all() {
+		var bookmark models.Bookmark
+		if err := bson.Unmarshal(raw, &bookmark); err != nil {
+			return nil, err
+		}
+		if bookmark.User == filter.User && isAfter(bookmark.CreatedAt, bookmark.ID, filter.After) {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+	sortNewestFirst(bookmarks, func(b *models.Bookmark) (time.Time, primitive.ObjectID) {
+		return b.CreatedAt, b.ID
+	})
+	start, end := page(len(bookmarks), 0, filter.Limit)
+
+	lostItems := s.m.collection("lostitems")
+	var results []bson.M
+	for _, bookmark := range bookmarks[start:end] {
+		raw, _ := s.coll().get(bookmark.ID)
+		var doc bson.M
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+
+		delete(doc, "lostItem")
+		if itemRaw, found := lostItems.get(bookmark.LostItem); found {
+			var item bson.M
+			if err := bson.Unmarshal(itemRaw, &item); err != nil {
+				return nil, err
+			}
+			doc["lostItem"] = item
+		}
+		results = append(results, doc)
+	}
+	return results, nil
+}
+
+func (s *memoryBookmarks) Count(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var n int64
+	for _, raw := range s.coll().all() {
+		if user, _ := raw.Lookup("user").ObjectIDOK(); user == userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memoryBookmarks) DeleteOwned(ctx context.Context, id, userID primitive.ObjectID) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	var bookmark models.Bookmark
+	if err := bson.Unmarshal(raw, &bookmark); err != nil {
+		return err
+	}
+	if bookmark.User != userID {
+		return ErrNotFound
+	}
+	s.coll().remove(id)
+	return nil
+}
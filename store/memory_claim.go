@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryClaims struct {
+	m *Memory
+}
+
+func (s *memoryClaims) coll() *memCollection { return s.m.collection("claims") }
+
+func (s *memoryClaims) Insert(ctx context.Context, claim *models.Claim) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	id, err := s.coll().insert(claim)
+	if err != nil {
+		return err
+	}
+	claim.ID = id
+	return nil
+}
+
+// find decodes one claim. Callers must hold s.m.mu.
+func (s *memoryClaims) find(id primitive.ObjectID) (*models.Claim, error) {
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var claim models.Claim
+	if err := bson.Unmarshal(raw, &claim); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (s *memoryClaims) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Claim, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	return s.find(id)
+}
+
+func (s *memoryClaims) List(ctx context.Context, filter ClaimFilter) ([]models.Claim, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var claims []models.Claim
+	for _, raw := range s.coll().all() {
+		var claim models.Claim
+		if err := bson.Unmarshal(raw, &claim); err != nil {
+			return nil, err
+		}
+		if filter.matches(&claim) {
+			claims = append(claims, claim)
+		}
+	}
+	sort.SliceStable(claims, func(i, j int) bool {
+		if !claims[i].CreatedAt.Equal(claims[j].CreatedAt) {
+			return claims[i].CreatedAt.After(claims[j].CreatedAt)
+		}
+		return claims[i].ID.Hex() > claims[j].ID.Hex()
+	})
+	start, end := page(len(claims), 0, filter.Limit)
+	return claims[start:end], nil
+}
+
+func (s *memoryClaims) Count(ctx context.Context, filter ClaimFilter) (int64, error) {
+	filter.After, filter.Limit = nil, 0
+	claims, err := s.List(ctx, filter)
+	return int64(len(claims)), err
+}
+
+// updatePending applies fn to the claim only while it is still pending.
+func (s *memoryClaims) updatePending(id primitive.ObjectID, fn func(claim *models.Claim) bool) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	claim, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	if claim.Status != models.ClaimPending || !fn(claim) {
+		return ErrNotFound
+	}
+	return s.coll().replace(id, claim)
+}
+
+func (s *memoryClaims) AddQuestion(ctx context.Context, id primitive.ObjectID, q models.VerificationQuestion) error {
+	return s.updatePending(id, func(claim *models.Claim) bool {
+		claim.Questions = append(claim.Questions, q)
+		claim.UpdatedAt = time.Now()
+		return true
+	})
+}
+
+func (s *memoryClaims) AnswerQuestion(ctx context.Context, id, questionID primitive.ObjectID, answer string) error {
+	return s.updatePending(id, func(claim *models.Claim) bool {
+		for i := range claim.Questions {
+			if claim.Questions[i].ID == questionID {
+				now := time.Now()
+				claim.Questions[i].Answer = answer
+				claim.Questions[i].AnsweredAt = now
+				claim.UpdatedAt = now
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Decide enforces the Mongo store's unique index on approved claims: a
+// second approval for the same found item is a duplicate key error.
+func (s *memoryClaims) Decide(ctx context.Context, id primitive.ObjectID, status models.ClaimStatus, note string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	claim, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	if claim.Status != models.ClaimPending {
+		return ErrNotFound
+	}
+	if status == models.ClaimApproved {
+		for _, raw := range s.coll().all() {
+			var other models.Claim
+			if err := bson.Unmarshal(raw, &other); err != nil {
+				return err
+			}
+			if other.FoundItem == claim.FoundItem && other.Status == models.ClaimApproved {
+				return duplicateKeyError("claims", "approved_foundItem")
+			}
+		}
+	}
+
+	now := time.Now()
+	claim.Status = status
+	claim.DecisionNote = note
+	claim.DecidedAt = now
+	claim.UpdatedAt = now
+	return s.coll().replace(id, claim)
+}
@@ -0,0 +1,283 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryConversations struct {
+	m *Memory
+}
+
+func (s *memoryConversations) coll() *memCollection { return s.m.collection("conversations") }
+
+// each decodes every conversation in natural order. Callers must hold s.m.mu.
+func (s *memoryConversations) each(fn func(conv *models.Conversation) error) error {
+	for _, raw := range s.coll().all() {
+		var conv models.Conversation
+		if err := bson.Unmarshal(raw, &conv); err != nil {
+			return err
+		}
+		if err := fn(&conv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find decodes one conversation. Callers must hold s.m.mu.
+func (s *memoryConversations) find(id primitive.ObjectID) (*models.Conversation, error) {
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var conv models.Conversation
+	if err := bson.Unmarshal(raw, &conv); err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+func (s *memoryConversations) FindOrCreate(ctx context.Context, conv *models.Conversation) (bool, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var existing *models.Conversation
+	err := s.each(func(c *models.Conversation) error {
+		if c.LostItem == conv.LostItem && c.FoundItem == conv.FoundItem {
+			existing = c
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		*conv = *existing
+		return false, nil
+	}
+
+	id, err := s.coll().insert(conv)
+	if err != nil {
+		return false, err
+	}
+	stored, err := s.find(id)
+	if err != nil {
+		return false, err
+	}
+	*conv = *stored
+	return true, nil
+}
+
+func (s *memoryConversations) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Conversation, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	return s.find(id)
+}
+
+func (s *memoryConversations) ListForUser(ctx context.Context, filter ConversationFilter) ([]models.Conversation, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var convs []models.Conversation
+	err := s.each(func(conv *models.Conversation) error {
+		if filter.matches(conv) {
+			convs = append(convs, *conv)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortNewestFirst(convs, func(conv *models.Conversation) (time.Time, primitive.ObjectID) {
+		return conv.CreatedAt, conv.ID
+	})
+	start, end := page(len(convs), 0, filter.Limit)
+	return convs[start:end], nil
+}
+
+// CountUnread mirrors the Mongo store's lookup of each conversation's
+// messages newer than the user's read mark.
+func (s *memoryConversations) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	messages := &memoryMessages{m: s.m}
+	var unread int64
+	err := s.each(func(conv *models.Conversation) error {
+		if !conv.HasParticipant(userID) {
+			return nil
+		}
+		inConversation, err := messages.inConversation(conv.ID)
+		if err != nil {
+			return err
+		}
+		since := conv.ReadAt(userID).Truncate(time.Millisecond)
+		for _, message := range inConversation {
+			if message.Sender != userID && message.CreatedAt.After(since) {
+				unread++
+			}
+		}
+		return nil
+	})
+	return unread, err
+}
+
+func (s *memoryConversations) CountForUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	convs, err := s.ListForUser(ctx, ConversationFilter{User: userID})
+	return int64(len(convs)), err
+}
+
+func (s *memoryConversations) Touch(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	return s.coll().set(id, bson.D{
+		{Key: "lastMessageAt", Value: at},
+		{Key: "updatedAt", Value: at},
+	})
+}
+
+func (s *memoryConversations) MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	conv, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	at = at.Truncate(time.Millisecond)
+	switch userID {
+	case conv.Owner:
+		if at.After(conv.OwnerReadAt) {
+			conv.OwnerReadAt = at
+		}
+	case conv.Finder:
+		if at.After(conv.FinderReadAt) {
+			conv.FinderReadAt = at
+		}
+	default:
+		return ErrNotFound
+	}
+	return s.coll().replace(id, conv)
+}
+
+type memoryMessages struct {
+	m *Memory
+}
+
+func (s *memoryMessages) coll() *memCollection { return s.m.collection("messages") }
+
+func (s *memoryMessages) Insert(ctx context.Context, message *models.Message) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	id, err := s.coll().insert(message)
+	if err != nil {
+		return err
+	}
+	message.ID = id
+	return nil
+}
+
+// inConversation decodes a conversation's messages, newest first. Callers
+// must hold s.m.mu.
+func (s *memoryMessages) inConversation(conversationID primitive.ObjectID) ([]models.Message, error) {
+	var messages []models.Message
+	for _, raw := range s.coll().all() {
+		var message models.Message
+		if err := bson.Unmarshal(raw, &message); err != nil {
+			return nil, err
+		}
+		if message.Conversation == conversationID {
+			messages = append(messages, message)
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.After(messages[j].CreatedAt)
+		}
+		return messages[i].ID.Hex() > messages[j].ID.Hex()
+	})
+	return messages, nil
+}
+
+func (s *memoryMessages) List(ctx context.Context, filter MessageFilter) ([]models.Message, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	messages, err := s.inConversation(filter.Conversation)
+	if err != nil {
+		return nil, err
+	}
+	messages = slices.DeleteFunc(messages, func(message models.Message) bool {
+		return !isAfter(message.CreatedAt, message.ID, filter.After)
+	})
+	start, end := page(len(messages), 0, filter.Limit)
+	return messages[start:end], nil
+}
+
+func (s *memoryMessages) Count(ctx context.Context, conversationID primitive.ObjectID) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	messages, err := s.inConversation(conversationID)
+	return int64(len(messages)), err
+}
+
+func (s *memoryMessages) CountUnread(ctx context.Context, conversationID, userID primitive.ObjectID, since time.Time) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	messages, err := s.inConversation(conversationID)
+	if err != nil {
+		return 0, err
+	}
+	since = since.Truncate(time.Millisecond)
+	var unread int64
+	for _, message := range messages {
+		if message.Sender != userID && message.CreatedAt.After(since) {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+func (s *memoryMessages) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Message, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var message models.Message
+	if err := bson.Unmarshal(raw, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (s *memoryMessages) SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	var message models.Message
+	if err := bson.Unmarshal(raw, &message); err != nil {
+		return err
+	}
+	message.Hidden = hidden
+	return s.coll().replace(id, &message)
+}
@@ -0,0 +1,246 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryFoundItems struct {
+	m *Memory
+}
+
+func (s *memoryFoundItems) coll() *memCollection { return s.m.collection("founditems") }
+
+func (s *memoryFoundItems) Insert(ctx context.Context, item *models.FoundItem) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	id, err := s.coll().insert(item)
+	if err != nil {
+		return err
+	}
+	item.ID = id
+	return nil
+}
+
+// find decodes one item. Callers must hold s.m.mu.
+func (s *memoryFoundItems) find(id primitive.ObjectID) (*models.FoundItem, error) {
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var item models.FoundItem
+	if err := bson.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *memoryFoundItems) FindByID(ctx context.Context, id primitive.ObjectID) (*models.FoundItem, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	return s.find(id)
+}
+
+func (s *memoryFoundItems) Count(ctx context.Context, filter FoundItemFilter) (int64, error) {
+	filter.After, filter.Limit, filter.Skip = nil, 0, 0
+	items, err := s.List(ctx, filter)
+	return int64(len(items)), err
+}
+
+func (s *memoryFoundItems) List(ctx context.Context, filter FoundItemFilter) ([]models.FoundItem, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var items []models.FoundItem
+	for _, raw := range s.coll().all() {
+		var item models.FoundItem
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, err
+		}
+		if !filter.matches(&item) {
+			continue
+		}
+		if filter.Near != nil {
+			d, inside := filter.Near.distance(item.Position)
+			if !inside {
+				continue
+			}
+			item.Distance = &d
+		}
+		if filter.Text != nil {
+			score, ok := textsearch.Score(*filter.Text, foundItemText(&item))
+			if !ok {
+				continue
+			}
+			item.Score = &score
+		}
+		items = append(items, item)
+	}
+
+	switch {
+	case filter.Near != nil:
+		sort.SliceStable(items, func(i, j int) bool { return *items[i].Distance < *items[j].Distance })
+	case filter.Text != nil:
+		sort.SliceStable(items, func(i, j int) bool { return *items[i].Score > *items[j].Score })
+	default:
+		foundItemSorts.sort(items, filter.Sort, func(item *models.FoundItem) primitive.ObjectID { return item.ID })
+	}
+
+	start, end := page(len(items), filter.Skip, filter.Limit)
+	return items[start:end], nil
+}
+
+// findOwned returns the item only if it was reported by owner. Callers must
+// hold s.m.mu.
+func (s *memoryFoundItems) findOwned(id, owner primitive.ObjectID) (*models.FoundItem, error) {
+	item, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if item.FoundPerson != owner {
+		return nil, ErrNotFound
+	}
+	return item, nil
+}
+
+func (s *memoryFoundItems) UpdateOwned(ctx context.Context, id, owner primitive.ObjectID, update FoundItemUpdate) (*models.FoundItem, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	item, err := s.findOwned(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	update.apply(item, time.Now())
+	if err := s.coll().replace(id, item); err != nil {
+		return nil, err
+	}
+	return s.find(id)
+}
+
+func (s *memoryFoundItems) SetImages(ctx context.Context, id, owner primitive.ObjectID, version int, images []models.Image) (*models.FoundItem, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	item, err := s.findOwned(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if item.ImagesVersion != version {
+		return nil, ErrConflict
+	}
+	item.SetImages(images)
+	item.ImagesVersion = version + 1
+	item.UpdatedAt = time.Now()
+	if err := s.coll().replace(id, item); err != nil {
+		return nil, err
+	}
+	return s.find(id)
+}
+
+func (s *memoryFoundItems) SetFound(ctx context.Context, id primitive.ObjectID, found bool, lostItem, lostPerson primitive.ObjectID) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	set := bson.D{{Key: foundItemFound, Value: found}}
+	if !lostItem.IsZero() {
+		set = append(set, bson.E{Key: foundItemLostItem, Value: lostItem})
+	}
+	if !lostPerson.IsZero() {
+		set = append(set, bson.E{Key: foundItemLostPerson, Value: lostPerson})
+	}
+	return s.coll().set(id, set)
+}
+
+func (s *memoryFoundItems) DeleteOwned(ctx context.Context, id, owner primitive.ObjectID) (*models.FoundItem, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	item, err := s.findOwned(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	s.coll().remove(id)
+	return item, nil
+}
+
+// matches is the in-memory equivalent of query.
+func (f FoundItemFilter) matches(item *models.FoundItem) bool {
+	if !f.LostItem.IsZero() && item.LostItem != f.LostItem {
+		return false
+	}
+	if !f.LostPerson.IsZero() && item.LostPerson != f.LostPerson {
+		return false
+	}
+	if !f.FoundPerson.IsZero() && item.FoundPerson != f.FoundPerson {
+		return false
+	}
+	if f.Found != nil && item.Found != *f.Found {
+		return false
+	}
+	if len(f.Categories) > 0 && !isAnyOf(item.Category, f.Categories) {
+		return false
+	}
+	if f.State != "" && !strings.EqualFold(item.State, f.State) {
+		return false
+	}
+	if f.HasImage != nil && (item.Image != "") != *f.HasImage {
+		return false
+	}
+	if f.HashedImages && !slices.ContainsFunc(item.Images, func(image models.Image) bool { return image.Hash != "" }) {
+		return false
+	}
+	if !f.Visibility.matches(item.Hidden) || !isAfter(item.CreatedAt, item.ID, f.After) {
+		return false
+	}
+	return inDateRange(item.DateFound, f.FoundAfter, f.FoundBefore)
+}
+
+func (s *memoryFoundItems) SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	item, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	item.Hidden = hidden
+	item.UpdatedAt = time.Now()
+	return s.coll().replace(id, item)
+}
+
+func (s *memoryFoundItems) Delete(ctx context.Context, id primitive.ObjectID) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if !s.coll().remove(id) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *memoryFoundItems) Images(ctx context.Context) ([]models.Image, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var images []models.Image
+	for _, raw := range s.coll().all() {
+		var item models.FoundItem
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, err
+		}
+		images = append(images, item.ImageList()...)
+	}
+	return images, nil
+}
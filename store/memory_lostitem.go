@@ -0,0 +1,273 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"lostfound-backend/models"
+	"lostfound-backend/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryLostItems struct {
+	m *Memory
+}
+
+func (s *memoryLostItems) coll() *memCollection { return s.m.collection("lostitems") }
+
+func (s *memoryLostItems) Insert(ctx context.Context, item *models.LostItem) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	id, err := s.coll().insert(item)
+	if err != nil {
+		return err
+	}
+	item.ID = id
+	return nil
+}
+
+func (s *memoryLostItems) FindByID(ctx context.Context, id primitive.ObjectID) (*models.LostItem, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var item models.LostItem
+	if err := bson.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *memoryLostItems) Count(ctx context.Context, filter LostItemFilter) (int64, error) {
+	filter.After, filter.Limit, filter.Skip = nil, 0, 0
+	items, err := s.List(ctx, filter)
+	return int64(len(items)), err
+}
+
+func (s *memoryLostItems) List(ctx context.Context, filter LostItemFilter) ([]models.LostItem, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var items []models.LostItem
+	for _, raw := range s.coll().all() {
+		var item models.LostItem
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, err
+		}
+		if !filter.matches(&item) {
+			continue
+		}
+		if filter.Near != nil {
+			d, inside := filter.Near.distance(item.Position)
+			if !inside {
+				continue
+			}
+			item.Distance = &d
+		}
+		if filter.Text != nil {
+			score, ok := textsearch.Score(*filter.Text, lostItemText(&item))
+			if !ok {
+				continue
+			}
+			item.Score = &score
+		}
+		items = append(items, item)
+	}
+
+	switch {
+	case filter.Near != nil:
+		sort.SliceStable(items, func(i, j int) bool { return *items[i].Distance < *items[j].Distance })
+	case filter.Text != nil:
+		sort.SliceStable(items, func(i, j int) bool { return *items[i].Score > *items[j].Score })
+	default:
+		lostItemSorts.sort(items, filter.Sort, func(item *models.LostItem) primitive.ObjectID { return item.ID })
+	}
+
+	start, end := page(len(items), filter.Skip, filter.Limit)
+	return items[start:end], nil
+}
+
+func (s *memoryLostItems) Distinct(ctx context.Context, field string) ([]string, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	return s.coll().distinct(field), nil
+}
+
+func (s *memoryLostItems) UpdateOwned(ctx context.Context, id, owner primitive.ObjectID, update LostItemUpdate) (*models.LostItem, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var item models.LostItem
+	if err := bson.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	if item.CreatedBy != owner {
+		return nil, ErrNotFound
+	}
+	if update.Images != nil && item.ImagesVersion != update.ImagesVersion {
+		return nil, ErrConflict
+	}
+	update.apply(&item, time.Now())
+	if err := s.coll().replace(id, &item); err != nil {
+		return nil, err
+	}
+
+	// Re-read so the caller sees the stored (millisecond) times
+	var updated models.LostItem
+	raw, _ = s.coll().get(id)
+	if err := bson.Unmarshal(raw, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (s *memoryLostItems) SetStatus(ctx context.Context, id primitive.ObjectID, change models.LostItemStatusChange) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	var item models.LostItem
+	if err := bson.Unmarshal(raw, &item); err != nil {
+		return err
+	}
+	if item.CurrentStatus() != change.From {
+		return ErrNotFound
+	}
+	item.Status = change.To
+	item.StatusHistory = append(item.StatusHistory, change)
+	item.UpdatedAt = change.At
+	return s.coll().replace(id, &item)
+}
+
+func (s *memoryLostItems) ExpireStale(ctx context.Context, cutoff time.Time) (int64, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	now := time.Now()
+	var expired int64
+	for _, raw := range s.coll().all() {
+		var item models.LostItem
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return expired, err
+		}
+		if item.CurrentStatus() != models.LostItemOpen || !item.DateLost.Before(cutoff.Truncate(time.Millisecond)) {
+			continue
+		}
+		item.Status = models.LostItemExpired
+		item.StatusHistory = append(item.StatusHistory, models.LostItemStatusChange{
+			From: models.LostItemOpen,
+			To:   models.LostItemExpired,
+			At:   now,
+			Note: "No activity before expiry",
+		})
+		item.UpdatedAt = now
+		if err := s.coll().replace(item.ID, &item); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (s *memoryLostItems) DeleteOwned(ctx context.Context, id, owner primitive.ObjectID) (*models.LostItem, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var item models.LostItem
+	if err := bson.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	if item.CreatedBy != owner {
+		return nil, ErrNotFound
+	}
+	s.coll().remove(id)
+	return &item, nil
+}
+
+// matches is the in-memory equivalent of query.
+func (f LostItemFilter) matches(item *models.LostItem) bool {
+	if !contains(item.Name, f.Name) || !contains(item.District, f.District) {
+		return false
+	}
+	if len(f.Categories) > 0 && !isAnyOf(item.Category, f.Categories) {
+		return false
+	}
+	if f.State != "" && !strings.EqualFold(item.State, f.State) {
+		return false
+	}
+	if f.HasImage != nil && (item.ImageURL != "") != *f.HasImage {
+		return false
+	}
+	if !f.CreatedBy.IsZero() && item.CreatedBy != f.CreatedBy {
+		return false
+	}
+	if len(f.Statuses) > 0 && !hasStatus(item, f.Statuses) {
+		return false
+	}
+	if !f.Visibility.matches(item.Hidden) || !isAfter(item.CreatedAt, item.ID, f.After) {
+		return false
+	}
+	return inDateRange(item.DateLost, f.LostAfter, f.LostBefore)
+}
+
+func (s *memoryLostItems) SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	var item models.LostItem
+	if err := bson.Unmarshal(raw, &item); err != nil {
+		return err
+	}
+	item.Hidden = hidden
+	item.UpdatedAt = time.Now()
+	return s.coll().replace(id, &item)
+}
+
+func (s *memoryLostItems) Delete(ctx context.Context, id primitive.ObjectID) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if !s.coll().remove(id) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *memoryLostItems) Images(ctx context.Context) ([]models.Image, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var images []models.Image
+	for _, raw := range s.coll().all() {
+		var item models.LostItem
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, err
+		}
+		images = append(images, item.ImageList()...)
+	}
+	return images, nil
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryMatches struct {
+	m *Memory
+}
+
+func (s *memoryMatches) coll() *memCollection { return s.m.collection("matches") }
+
+// each decodes every match in natural order. Callers must hold s.m.mu.
+func (s *memoryMatches) each(fn func(match *models.Match) error) error {
+	for _, raw := range s.coll().all() {
+		var match models.Match
+		if err := bson.Unmarshal(raw, &match); err != nil {
+			return err
+		}
+		if err := fn(&match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryMatches) Upsert(ctx context.Context, match *models.Match) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	now := time.Now()
+	var existing *models.Match
+	err := s.each(func(m *models.Match) error {
+		if m.LostItem == match.LostItem && m.FoundItem == match.FoundItem {
+			existing = m
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		match.ID = primitive.NilObjectID
+		match.CreatedAt = now
+		match.UpdatedAt = now
+		id, err := s.coll().insert(match)
+		if err != nil {
+			return err
+		}
+		match.ID = id
+		return nil
+	}
+
+	existing.Score = match.Score
+	existing.Breakdown = match.Breakdown
+	existing.UpdatedAt = now
+	if err := s.coll().replace(existing.ID, existing); err != nil {
+		return err
+	}
+	*match = *existing
+	return nil
+}
+
+func (s *memoryMatches) List(ctx context.Context, filter MatchFilter) ([]models.Match, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var matches []models.Match
+	err := s.each(func(m *models.Match) error {
+		if !filter.matches(m) {
+			return nil
+		}
+		if filter.Viewer != nil {
+			visible, err := s.visible(filter, m)
+			if err != nil || !visible {
+				return err
+			}
+		}
+		matches = append(matches, *m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID.Hex() < matches[j].ID.Hex()
+	})
+	start, end := page(len(matches), filter.Skip, filter.Limit)
+	return matches[start:end], nil
+}
+
+// visible mirrors the Mongo store's lookup of the match's other item.
+// Callers must hold s.m.mu.
+func (s *memoryMatches) visible(filter MatchFilter, m *models.Match) (bool, error) {
+	collection, _, _ := filter.other()
+	if collection == "lostitems" {
+		raw, ok := s.m.collection(collection).get(m.LostItem)
+		if !ok {
+			return false, nil
+		}
+		var item models.LostItem
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return false, err
+		}
+		return filter.visibleTo(item.Hidden, item.CreatedBy), nil
+	}
+	raw, ok := s.m.collection(collection).get(m.FoundItem)
+	if !ok {
+		return false, nil
+	}
+	var item models.FoundItem
+	if err := bson.Unmarshal(raw, &item); err != nil {
+		return false, err
+	}
+	return filter.visibleTo(item.Hidden, item.FoundPerson), nil
+}
+
+func (s *memoryMatches) Count(ctx context.Context, filter MatchFilter) (int64, error) {
+	filter.Limit, filter.Skip = 0, 0
+	matches, err := s.List(ctx, filter)
+	return int64(len(matches)), err
+}
+
+func (s *memoryMatches) ListByLostItem(ctx context.Context, lostItemID primitive.ObjectID) ([]models.Match, error) {
+	return s.List(ctx, MatchFilter{LostItem: lostItemID})
+}
+
+func (s *memoryMatches) ListByFoundItem(ctx context.Context, foundItemID primitive.ObjectID) ([]models.Match, error) {
+	return s.List(ctx, MatchFilter{FoundItem: foundItemID})
+}
+
+func (s *memoryMatches) deleteWhere(drop func(m *models.Match) bool) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var ids []primitive.ObjectID
+	err := s.each(func(m *models.Match) error {
+		if drop(m) {
+			ids = append(ids, m.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.coll().remove(id)
+	}
+	return nil
+}
+
+func (s *memoryMatches) DeleteByLostItem(ctx context.Context, lostItemID primitive.ObjectID) error {
+	return s.deleteWhere(func(m *models.Match) bool { return m.LostItem == lostItemID })
+}
+
+func (s *memoryMatches) DeleteByFoundItem(ctx context.Context, foundItemID primitive.ObjectID) error {
+	return s.deleteWhere(func(m *models.Match) bool { return m.FoundItem == foundItemID })
+}
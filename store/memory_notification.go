@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryNotifications struct {
+	m *Memory
+}
+
+func (s *memoryNotifications) coll() *memCollection { return s.m.collection("notifications") }
+
+func (s *memoryNotifications) Insert(ctx context.Context, n *models.Notification) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	id, err := s.coll().insert(n)
+	if err != nil {
+		return err
+	}
+	n.ID = id
+	return nil
+}
+
+// filter decodes the notifications matching f, newest first. Callers must
+// hold s.m.mu.
+func (s *memoryNotifications) filter(f NotificationFilter) ([]models.Notification, error) {
+	var notifications []models.Notification
+	for _, raw := range s.coll().all() {
+		var n models.Notification
+		if err := bson.Unmarshal(raw, &n); err != nil {
+			return nil, err
+		}
+		if f.matches(&n) {
+			notifications = append(notifications, n)
+		}
+	}
+	sort.SliceStable(notifications, func(i, j int) bool {
+		if !notifications[i].CreatedAt.Equal(notifications[j].CreatedAt) {
+			return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+		}
+		return notifications[i].ID.Hex() > notifications[j].ID.Hex()
+	})
+	return notifications, nil
+}
+
+func (s *memoryNotifications) List(ctx context.Context, filter NotificationFilter) ([]models.Notification, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	notifications, err := s.filter(filter)
+	if err != nil {
+		return nil, err
+	}
+	start, end := page(len(notifications), 0, filter.Limit)
+	return notifications[start:end], nil
+}
+
+func (s *memoryNotifications) Count(ctx context.Context, filter NotificationFilter) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	filter.After = nil
+	notifications, err := s.filter(filter)
+	return int64(len(notifications)), err
+}
+
+func (s *memoryNotifications) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	notifications, err := s.filter(NotificationFilter{User: userID, UnreadOnly: true})
+	return int64(len(notifications)), err
+}
+
+func (s *memoryNotifications) MarkRead(ctx context.Context, id, userID primitive.ObjectID) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	var n models.Notification
+	if err := bson.Unmarshal(raw, &n); err != nil {
+		return err
+	}
+	if n.User != userID {
+		return ErrNotFound
+	}
+	if n.ReadAt == nil {
+		now := time.Now()
+		n.ReadAt = &now
+	}
+	n.Read = true
+	return s.coll().replace(id, &n)
+}
+
+func (s *memoryNotifications) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	unread, err := s.filter(NotificationFilter{User: userID, UnreadOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	for _, n := range unread {
+		n.Read = true
+		n.ReadAt = &now
+		if err := s.coll().replace(n.ID, &n); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(unread)), nil
+}
+
+func (s *memoryNotifications) DeleteOwned(ctx context.Context, id, userID primitive.ObjectID) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	var n models.Notification
+	if err := bson.Unmarshal(raw, &n); err != nil {
+		return err
+	}
+	if n.User != userID {
+		return ErrNotFound
+	}
+	s.coll().remove(id)
+	return nil
+}
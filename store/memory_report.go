@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryReports struct {
+	m *Memory
+}
+
+func (s *memoryReports) coll() *memCollection { return s.m.collection("reports") }
+
+// filter decodes the reports matching f, oldest first. Callers must hold
+// s.m.mu.
+func (s *memoryReports) filter(f ReportFilter) ([]models.Report, error) {
+	var reports []models.Report
+	for _, raw := range s.coll().all() {
+		var report models.Report
+		if err := bson.Unmarshal(raw, &report); err != nil {
+			return nil, err
+		}
+		if f.matches(&report) {
+			reports = append(reports, report)
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		if !reports[i].CreatedAt.Equal(reports[j].CreatedAt) {
+			return reports[i].CreatedAt.Before(reports[j].CreatedAt)
+		}
+		return reports[i].ID.Hex() < reports[j].ID.Hex()
+	})
+	return reports, nil
+}
+
+func (s *memoryReports) Insert(ctx context.Context, report *models.Report) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	// Mirrors the partial unique index on open reports
+	open, err := s.filter(ReportFilter{Status: models.ReportOpen, TargetType: report.TargetType, Target: report.Target})
+	if err != nil {
+		return err
+	}
+	for _, other := range open {
+		if report.Status == models.ReportOpen && other.Reporter == report.Reporter {
+			return duplicateKeyError("reports", "open_target_reporter")
+		}
+	}
+
+	id, err := s.coll().insert(report)
+	if err != nil {
+		return err
+	}
+	report.ID = id
+	return nil
+}
+
+func (s *memoryReports) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Report, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var report models.Report
+	if err := bson.Unmarshal(raw, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (s *memoryReports) List(ctx context.Context, filter ReportFilter) ([]models.Report, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	reports, err := s.filter(filter)
+	if err != nil {
+		return nil, err
+	}
+	start, end := page(len(reports), filter.Skip, filter.Limit)
+	return reports[start:end], nil
+}
+
+func (s *memoryReports) Count(ctx context.Context, filter ReportFilter) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	reports, err := s.filter(filter)
+	return int64(len(reports)), err
+}
+
+func (s *memoryReports) CountOpen(ctx context.Context, targetType models.TargetType, target primitive.ObjectID) (int64, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	reports, err := s.filter(ReportFilter{Status: models.ReportOpen, TargetType: targetType, Target: target})
+	return int64(len(reports)), err
+}
+
+func (s *memoryReports) Close(ctx context.Context, targetType models.TargetType, target primitive.ObjectID, status models.ReportStatus, decision models.ModerationAction) (int64, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	reports, err := s.filter(ReportFilter{Status: models.ReportOpen, TargetType: targetType, Target: target})
+	if err != nil {
+		return 0, err
+	}
+	for i := range reports {
+		reports[i].Status = status
+		reports[i].Decision = &decision
+		if err := s.coll().replace(reports[i].ID, &reports[i]); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(reports)), nil
+}
+
+type memoryAuditLog struct {
+	m *Memory
+}
+
+func (s *memoryAuditLog) coll() *memCollection { return s.m.collection("auditlog") }
+
+func (s *memoryAuditLog) Insert(ctx context.Context, entry *models.AuditEntry) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	id, err := s.coll().insert(entry)
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	return nil
+}
+
+func (s *memoryAuditLog) List(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var entries []models.AuditEntry
+	for _, raw := range s.coll().all() {
+		var entry models.AuditEntry
+		if err := bson.Unmarshal(raw, &entry); err != nil {
+			return nil, err
+		}
+		if filter.matches(&entry) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].ID.Hex() > entries[j].ID.Hex()
+	})
+	start, end := page(len(entries), 0, filter.Limit)
+	return entries[start:end], nil
+}
+
+func (s *memoryAuditLog) Count(ctx context.Context, filter AuditFilter) (int64, error) {
+	filter.After, filter.Limit = nil, 0
+	entries, err := s.List(ctx, filter)
+	return int64(len(entries)), err
+}
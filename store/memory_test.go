@@ -0,0 +1,220 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMemoryKeysetPages(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	// Several items per millisecond, so pages have to split ties on _id
+	for i := 0; i < 13; i++ {
+		item := models.LostItem{Name: "item", CreatedBy: alice, CreatedAt: epoch.Add(time.Duration(i/3) * time.Millisecond)}
+		if i%4 == 0 {
+			item.Hidden = hide
+		}
+		if err := m.LostItems().Insert(ctx, &item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lostItemID := func(item *models.LostItem) primitive.ObjectID { return item.ID }
+
+	for _, visibility := range []Visibility{VisibleItems, AllItems, HiddenItems} {
+		all, err := m.LostItems().List(ctx, LostItemFilter{Visibility: visibility})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for limit := int64(1); limit <= 5; limit++ {
+			filter := LostItemFilter{Visibility: visibility, Limit: limit}
+			var walked []models.LostItem
+			for pages := 0; ; pages++ {
+				if pages > len(all) {
+					t.Fatalf("visibility %d, limit %d: paging does not end", visibility, limit)
+				}
+				page, err := m.LostItems().List(ctx, filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				walked = append(walked, page...)
+				if int64(len(page)) < limit {
+					break
+				}
+				last := page[len(page)-1]
+				filter.After = cursorAt(last.CreatedAt, last.ID)
+			}
+			if got, want := idsOf(walked, lostItemID), idsOf(all, lostItemID); !slices.Equal(got, want) {
+				t.Errorf("visibility %d, limit %d: pages give %v, want %v", visibility, limit, got, want)
+			}
+			n, err := m.LostItems().Count(ctx, filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(all)) {
+				t.Errorf("visibility %d: Count() = %d with a cursor set, want %d", visibility, n, len(all))
+			}
+		}
+	}
+}
+
+func TestMemoryOwnedWrites(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	lost := models.LostItem{Name: "wallet", CreatedBy: alice, CreatedAt: epoch}
+	if err := m.LostItems().Insert(ctx, &lost); err != nil {
+		t.Fatal(err)
+	}
+	found := models.FoundItem{Name: "wallet", FoundPerson: alice, CreatedAt: epoch}
+	if err := m.FoundItems().Insert(ctx, &found); err != nil {
+		t.Fatal(err)
+	}
+	name := "purse"
+	images := []models.Image{{ID: primitive.NewObjectID(), URL: "/a.jpg"}}
+
+	// Someone else's item is reported missing, not forbidden, so its
+	// existence does not leak
+	if _, err := m.LostItems().UpdateOwned(ctx, lost.ID, bob, LostItemUpdate{Name: &name}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateOwned by another user: error = %v, want ErrNotFound", err)
+	}
+	if _, err := m.LostItems().DeleteOwned(ctx, lost.ID, bob); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteOwned by another user: error = %v, want ErrNotFound", err)
+	}
+	if _, err := m.FoundItems().UpdateOwned(ctx, found.ID, bob, FoundItemUpdate{Name: &name}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("found UpdateOwned by another user: error = %v, want ErrNotFound", err)
+	}
+	if _, err := m.FoundItems().SetImages(ctx, found.ID, bob, 0, images); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SetImages by another user: error = %v, want ErrNotFound", err)
+	}
+	if _, err := m.FoundItems().DeleteOwned(ctx, found.ID, bob); !errors.Is(err, ErrNotFound) {
+		t.Errorf("found DeleteOwned by another user: error = %v, want ErrNotFound", err)
+	}
+
+	// Image writes at a stale version conflict
+	updated, err := m.LostItems().UpdateOwned(ctx, lost.ID, alice, LostItemUpdate{Images: &images})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ImagesVersion != 1 || updated.ImageURL != "/a.jpg" {
+		t.Errorf("after an image update: version %d, image %q", updated.ImagesVersion, updated.ImageURL)
+	}
+	if _, err := m.LostItems().UpdateOwned(ctx, lost.ID, alice, LostItemUpdate{Images: &images}); !errors.Is(err, ErrConflict) {
+		t.Errorf("UpdateOwned at a stale version: error = %v, want ErrConflict", err)
+	}
+	// Other fields do not need the version
+	if _, err := m.LostItems().UpdateOwned(ctx, lost.ID, alice, LostItemUpdate{Name: &name}); err != nil {
+		t.Errorf("UpdateOwned without images: %v", err)
+	}
+	if _, err := m.FoundItems().SetImages(ctx, found.ID, alice, 0, images); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.FoundItems().SetImages(ctx, found.ID, alice, 0, nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("SetImages at a stale version: error = %v, want ErrConflict", err)
+	}
+}
+
+func TestMemoryConversationPagesIgnoreActivity(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	for i := 0; i < 3; i++ {
+		at := epoch.Add(time.Duration(i) * time.Hour)
+		conv := models.Conversation{LostItem: primitive.NewObjectID(), FoundItem: primitive.NewObjectID(), Owner: alice, Finder: bob, CreatedAt: at, UpdatedAt: at}
+		if _, err := m.Conversations().FindOrCreate(ctx, &conv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := m.Conversations().ListForUser(ctx, ConversationFilter{User: alice})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := m.Conversations().ListForUser(ctx, ConversationFilter{User: alice, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A message in the oldest conversation between the two pages must not
+	// move it onto the page already read
+	if err := m.Conversations().Touch(ctx, all[2].ID, epoch.Add(5*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	last := first[len(first)-1]
+	second, err := m.Conversations().ListForUser(ctx, ConversationFilter{User: alice, After: cursorAt(last.CreatedAt, last.ID), Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conversationID := func(conv *models.Conversation) primitive.ObjectID { return conv.ID }
+	if got, want := idsOf(append(first, second...), conversationID), idsOf(all, conversationID); !slices.Equal(got, want) {
+		t.Errorf("pages give %v, want %v", got, want)
+	}
+}
+
+func TestMemoryCountUnreadAcrossConversations(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	carol := primitive.NewObjectID()
+	var convs []models.Conversation
+	for _, pair := range [][2]primitive.ObjectID{{alice, bob}, {carol, alice}, {bob, carol}} {
+		conv := models.Conversation{LostItem: primitive.NewObjectID(), FoundItem: primitive.NewObjectID(), Owner: pair[0], Finder: pair[1], CreatedAt: epoch}
+		if _, err := m.Conversations().FindOrCreate(ctx, &conv); err != nil {
+			t.Fatal(err)
+		}
+		convs = append(convs, conv)
+	}
+	send := func(conv models.Conversation, sender primitive.ObjectID, at time.Time) {
+		message := models.Message{Conversation: conv.ID, Sender: sender, Body: "hi", CreatedAt: at}
+		if err := m.Messages().Insert(ctx, &message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send(convs[0], bob, epoch.Add(time.Minute))
+	send(convs[0], bob, epoch.Add(3*time.Minute))
+	send(convs[0], alice, epoch.Add(4*time.Minute)) // sent by alice
+	send(convs[1], carol, epoch.Add(time.Minute))
+	send(convs[2], bob, epoch.Add(time.Minute)) // alice is not in it
+	if err := m.Conversations().MarkRead(ctx, convs[0].ID, alice, epoch.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := m.Conversations().CountUnread(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("CountUnread() = %d, want 2", n)
+	}
+}
+
+func TestMemoryDecideApprovesOneClaimPerItem(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	item, other := primitive.NewObjectID(), primitive.NewObjectID()
+	var claims []models.Claim
+	for _, foundItem := range []primitive.ObjectID{item, item, other} {
+		claim := models.Claim{FoundItem: foundItem, Claimant: bob, Finder: alice, Status: models.ClaimPending}
+		if err := m.Claims().Insert(ctx, &claim); err != nil {
+			t.Fatal(err)
+		}
+		claims = append(claims, claim)
+	}
+
+	if err := m.Claims().Decide(ctx, claims[0].ID, models.ClaimApproved, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Claims().Decide(ctx, claims[1].ID, models.ClaimApproved, ""); !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("second approval for the item: error = %v, want a duplicate key error", err)
+	}
+	if err := m.Claims().Decide(ctx, claims[2].ID, models.ClaimApproved, ""); err != nil {
+		t.Errorf("approval for another item: %v", err)
+	}
+	if err := m.Claims().Decide(ctx, claims[1].ID, models.ClaimRejected, ""); err != nil {
+		t.Errorf("rejecting the second claim: %v", err)
+	}
+}
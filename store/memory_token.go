@@ -0,0 +1,236 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRefreshTokens struct {
+	m *Memory
+}
+
+func (s *memoryRefreshTokens) coll() *memCollection { return s.m.collection("refreshtokens") }
+
+// each decodes every token in natural order. Callers must hold s.m.mu.
+func (s *memoryRefreshTokens) each(fn func(token *models.RefreshToken) error) error {
+	for _, raw := range s.coll().all() {
+		var token models.RefreshToken
+		if err := bson.Unmarshal(raw, &token); err != nil {
+			return err
+		}
+		if err := fn(&token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryRefreshTokens) Insert(ctx context.Context, token *models.RefreshToken) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	return s.insert(token)
+}
+
+// insert stores the token. Callers must hold s.m.mu.
+func (s *memoryRefreshTokens) insert(token *models.RefreshToken) error {
+	id, err := s.coll().insert(token)
+	if err != nil {
+		return err
+	}
+	token.ID = id
+	return nil
+}
+
+func (s *memoryRefreshTokens) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var found *models.RefreshToken
+	err := s.each(func(token *models.RefreshToken) error {
+		if token.TokenHash == hash {
+			found = token
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+func (s *memoryRefreshTokens) Rotate(ctx context.Context, id primitive.ObjectID, next *models.RefreshToken) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	var token models.RefreshToken
+	if err := bson.Unmarshal(raw, &token); err != nil {
+		return err
+	}
+	if !token.UsedAt.IsZero() || !token.RevokedAt.IsZero() {
+		return ErrNotFound
+	}
+
+	if next.ID.IsZero() {
+		next.ID = primitive.NewObjectID()
+	}
+	token.UsedAt = next.CreatedAt
+	token.ReplacedBy = next.ID
+	if err := s.coll().replace(id, &token); err != nil {
+		return err
+	}
+	return s.insert(next)
+}
+
+func (s *memoryRefreshTokens) RevokeFamily(ctx context.Context, family primitive.ObjectID, at time.Time) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	return s.each(func(token *models.RefreshToken) error {
+		if token.Family != family || !token.RevokedAt.IsZero() {
+			return nil
+		}
+		token.RevokedAt = at
+		return s.coll().replace(token.ID, token)
+	})
+}
+
+type memoryRevokedTokens struct {
+	m *Memory
+}
+
+func (s *memoryRevokedTokens) coll() *memCollection { return s.m.collection("revokedtokens") }
+
+func (s *memoryRevokedTokens) Revoke(ctx context.Context, revoked *models.RevokedToken) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	id, err := s.coll().insert(revoked)
+	if err != nil {
+		return err
+	}
+	revoked.ID = id
+	return nil
+}
+
+func (s *memoryRevokedTokens) IsRevoked(ctx context.Context, jti string, family primitive.ObjectID) (bool, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	now := time.Now()
+	for _, raw := range s.coll().all() {
+		var revoked models.RevokedToken
+		if err := bson.Unmarshal(raw, &revoked); err != nil {
+			return false, err
+		}
+		if !revoked.ExpiresAt.After(now) {
+			continue
+		}
+		if (revoked.JTI != "" && revoked.JTI == jti) || (!family.IsZero() && revoked.Family == family) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryRefreshTokens) RevokeUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var families []primitive.ObjectID
+	seen := map[primitive.ObjectID]bool{}
+	err := s.each(func(token *models.RefreshToken) error {
+		if token.User != userID || !token.RevokedAt.IsZero() {
+			return nil
+		}
+		if !seen[token.Family] {
+			seen[token.Family] = true
+			families = append(families, token.Family)
+		}
+		token.RevokedAt = at
+		return s.coll().replace(token.ID, token)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return families, nil
+}
+
+type memoryOneTimeTokens struct {
+	m *Memory
+}
+
+func (s *memoryOneTimeTokens) coll() *memCollection { return s.m.collection("onetimetokens") }
+
+func (s *memoryOneTimeTokens) Insert(ctx context.Context, token *models.OneTimeToken) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, raw := range s.coll().all() {
+		var other models.OneTimeToken
+		if err := bson.Unmarshal(raw, &other); err != nil {
+			return err
+		}
+		if other.TokenHash == token.TokenHash {
+			return duplicateKeyError("onetimetokens", "tokenHash")
+		}
+	}
+	id, err := s.coll().insert(token)
+	if err != nil {
+		return err
+	}
+	token.ID = id
+	return nil
+}
+
+func (s *memoryOneTimeTokens) Consume(ctx context.Context, purpose models.TokenPurpose, hash string, at time.Time) (*models.OneTimeToken, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, raw := range s.coll().all() {
+		var token models.OneTimeToken
+		if err := bson.Unmarshal(raw, &token); err != nil {
+			return nil, err
+		}
+		if token.Purpose != purpose || token.TokenHash != hash {
+			continue
+		}
+		if !token.UsedAt.IsZero() || !token.ExpiresAt.After(at) {
+			return nil, ErrNotFound
+		}
+		token.UsedAt = at
+		if err := s.coll().replace(token.ID, &token); err != nil {
+			return nil, err
+		}
+		return &token, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memoryOneTimeTokens) DeleteForUser(ctx context.Context, userID primitive.ObjectID, purpose models.TokenPurpose) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, raw := range s.coll().all() {
+		var token models.OneTimeToken
+		if err := bson.Unmarshal(raw, &token); err != nil {
+			return err
+		}
+		if token.User == userID && token.Purpose == purpose {
+			s.coll().remove(token.ID)
+		}
+	}
+	return nil
+}
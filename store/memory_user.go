@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryUsers struct {
+	m *Memory
+}
+
+func (s *memoryUsers) coll() *memCollection { return s.m.collection("users") }
+
+func (s *memoryUsers) Insert(ctx context.Context, user *models.User) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if err := s.checkEmail(user.Email, primitive.NilObjectID); err != nil {
+		return err
+	}
+	id, err := s.coll().insert(user)
+	if err != nil {
+		return err
+	}
+	user.ID = id
+	return nil
+}
+
+// checkEmail enforces the unique index on email, ignoring the user except.
+// Callers must hold s.m.mu.
+func (s *memoryUsers) checkEmail(email string, except primitive.ObjectID) error {
+	for _, raw := range s.coll().all() {
+		stored, _ := raw.Lookup("email").StringValueOK()
+		if stored == email && raw.Lookup("_id").ObjectID() != except {
+			return duplicateKeyError("users", "email")
+		}
+	}
+	return nil
+}
+
+// find decodes one user. Callers must hold s.m.mu.
+func (s *memoryUsers) find(id primitive.ObjectID) (*models.User, error) {
+	raw, ok := s.coll().get(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var user models.User
+	if err := bson.Unmarshal(raw, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *memoryUsers) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	return s.find(id)
+}
+
+func (s *memoryUsers) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for _, raw := range s.coll().all() {
+		var user models.User
+		if err := bson.Unmarshal(raw, &user); err != nil {
+			return nil, err
+		}
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memoryUsers) UpdateProfile(ctx context.Context, id primitive.ObjectID, update ProfileUpdate) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	user.Username = update.Username
+	user.Phone = update.Phone
+	user.Profession = update.Profession
+	user.Location.District = update.District
+	user.Location.State = update.State
+	user.UpdatedAt = time.Now()
+	return s.coll().replace(id, user)
+}
+
+func (s *memoryUsers) SetPassword(ctx context.Context, id primitive.ObjectID, hash string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	user.UpdatedAt = time.Now()
+	return s.coll().replace(id, user)
+}
+
+func (s *memoryUsers) SetPendingEmail(ctx context.Context, id primitive.ObjectID, email string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	user.PendingEmail = email
+	user.UpdatedAt = time.Now()
+	return s.coll().replace(id, user)
+}
+
+func (s *memoryUsers) VerifyEmail(ctx context.Context, id primitive.ObjectID, email string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	if user.Email != email && user.PendingEmail != email {
+		return ErrNotFound
+	}
+	if err := s.checkEmail(email, id); err != nil {
+		return err
+	}
+	if user.PendingEmail == email {
+		user.PendingEmail = ""
+	}
+	user.Email = email
+	user.EmailVerified = true
+	user.UpdatedAt = time.Now()
+	return s.coll().replace(id, user)
+}
+
+func (s *memoryUsers) SetRole(ctx context.Context, id primitive.ObjectID, role models.Role) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	return s.coll().replace(id, user)
+}
+
+func (s *memoryUsers) SetSuspension(ctx context.Context, id primitive.ObjectID, suspension *models.Suspension) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	user.Suspension = suspension
+	user.UpdatedAt = time.Now()
+	return s.coll().replace(id, user)
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoBookmarks struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoBookmarks) Insert(ctx context.Context, bookmark *models.Bookmark) error {
+	result, err := s.coll.InsertOne(ctx, bookmark)
+	if err != nil {
+		return err
+	}
+	bookmark.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoBookmarks) ListWithLostItems(ctx context.Context, filter BookmarkFilter) ([]bson.M, error) {
+	matchStage := bson.D{{Key: "$match", Value: filter.query()}}
+	sortStage := bson.D{{Key: "$sort", Value: newestFirst("createdAt")}}
+	lookupStage := bson.D{
+		{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "lostitems"},
+			{Key: "localField", Value: "lostItem"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "lostItem"},
+		}},
+	}
+	unwindStage := bson.D{{Key: "$unwind", Value: bson.D{
+		{Key: "path", Value: "$lostItem"},
+		{Key: "preserveNullAndEmptyArrays", Value: true},
+	}}}
+
+	opts := options.Aggregate().SetMaxTime(5 * time.Second)
+	pipeline := mongo.Pipeline{matchStage, sortStage}
+	if filter.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: filter.Limit}})
+	}
+	pipeline = append(pipeline, lookupStage, unwindStage)
+	cursor, err := s.coll.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *mongoBookmarks) Count(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.coll.CountDocuments(ctx, bson.M{"user": userID})
+}
+
+func (s *mongoBookmarks) DeleteOwned(ctx context.Context, id, userID primitive.ObjectID) error {
+	result, err := s.coll.DeleteOne(ctx, bson.M{"_id": id, "user": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoClaims struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoClaims) Insert(ctx context.Context, claim *models.Claim) error {
+	result, err := s.coll.InsertOne(ctx, claim)
+	if err != nil {
+		return err
+	}
+	claim.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoClaims) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Claim, error) {
+	var claim models.Claim
+	err := s.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&claim)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (s *mongoClaims) List(ctx context.Context, filter ClaimFilter) ([]models.Claim, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cursor, err := s.coll.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var claims []models.Claim
+	if err := cursor.All(ctx, &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (s *mongoClaims) Count(ctx context.Context, filter ClaimFilter) (int64, error) {
+	filter.After = nil
+	return s.coll.CountDocuments(ctx, filter.query())
+}
+
+// updatePending applies update to the claim only while it is still pending.
+func (s *mongoClaims) updatePending(ctx context.Context, filter bson.M, update bson.M) error {
+	filter["status"] = models.ClaimPending
+	result, err := s.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoClaims) AddQuestion(ctx context.Context, id primitive.ObjectID, q models.VerificationQuestion) error {
+	return s.updatePending(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$push": bson.M{"questions": q},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+}
+
+func (s *mongoClaims) AnswerQuestion(ctx context.Context, id, questionID primitive.ObjectID, answer string) error {
+	now := time.Now()
+	return s.updatePending(ctx,
+		bson.M{"_id": id, "questions._id": questionID},
+		bson.M{"$set": bson.M{
+			"questions.$.answer":     answer,
+			"questions.$.answeredAt": now,
+			"updatedAt":              now,
+		}},
+	)
+}
+
+func (s *mongoClaims) Decide(ctx context.Context, id primitive.ObjectID, status models.ClaimStatus, note string) error {
+	now := time.Now()
+	return s.updatePending(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"status":       status,
+			"decisionNote": note,
+			"decidedAt":    now,
+			"updatedAt":    now,
+		}},
+	)
+}
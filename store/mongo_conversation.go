@@ -0,0 +1,216 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoConversations struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoConversations) FindOrCreate(ctx context.Context, conv *models.Conversation) (bool, error) {
+	filter := bson.M{"lostItem": conv.LostItem, "foundItem": conv.FoundItem}
+	result, err := s.coll.UpdateOne(ctx, filter,
+		bson.M{"$setOnInsert": conv},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	if err := s.coll.FindOne(ctx, filter).Decode(conv); err != nil {
+		return false, err
+	}
+	return result.UpsertedID != nil, nil
+}
+
+func (s *mongoConversations) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Conversation, error) {
+	var conv models.Conversation
+	err := s.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&conv)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+func (s *mongoConversations) ListForUser(ctx context.Context, filter ConversationFilter) ([]models.Conversation, error) {
+	opts := options.Find().SetSort(newestFirst("createdAt"))
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cursor, err := s.coll.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var convs []models.Conversation
+	if err := cursor.All(ctx, &convs); err != nil {
+		return nil, err
+	}
+	return convs, nil
+}
+
+func (s *mongoConversations) CountForUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.coll.CountDocuments(ctx, ConversationFilter{User: userID}.query())
+}
+
+// CountUnread counts in one query: each of the user's conversations looks
+// up its messages newer than the user's read mark and not sent by them.
+func (s *mongoConversations) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	matchStage := bson.D{{Key: "$match", Value: ConversationFilter{User: userID}.query()}}
+	readAt := bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$owner", userID}},
+		bson.M{"$ifNull": bson.A{"$ownerReadAt", time.Time{}}},
+		bson.M{"$ifNull": bson.A{"$finderReadAt", time.Time{}}},
+	}}
+	lookupStage := bson.D{
+		{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "messages"},
+			{Key: "let", Value: bson.M{"conversation": "$_id", "readAt": readAt}},
+			{Key: "pipeline", Value: mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$conversation", "$$conversation"}},
+					bson.M{"$ne": bson.A{"$sender", userID}},
+					bson.M{"$gt": bson.A{"$createdAt", "$$readAt"}},
+				}}}}},
+				{{Key: "$count", Value: "n"}},
+			}},
+			{Key: "as", Value: "unread"},
+		}},
+	}
+	unwindStage := bson.D{{Key: "$unwind", Value: "$unread"}}
+	groupStage := bson.D{{Key: "$group", Value: bson.M{"_id": nil, "n": bson.M{"$sum": "$unread.n"}}}}
+
+	opts := options.Aggregate().SetMaxTime(5 * time.Second)
+	cursor, err := s.coll.Aggregate(ctx, mongo.Pipeline{matchStage, lookupStage, unwindStage, groupStage}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		N int64 `bson:"n"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].N, nil
+}
+
+func (s *mongoConversations) Touch(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"lastMessageAt": at, "updatedAt": at}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoConversations) MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) error {
+	for _, role := range []struct{ participant, readAt string }{
+		{"owner", "ownerReadAt"},
+		{"finder", "finderReadAt"},
+	} {
+		result, err := s.coll.UpdateOne(ctx,
+			bson.M{"_id": id, role.participant: userID},
+			bson.M{"$max": bson.M{role.readAt: at}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount > 0 {
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+type mongoMessages struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoMessages) Insert(ctx context.Context, message *models.Message) error {
+	result, err := s.coll.InsertOne(ctx, message)
+	if err != nil {
+		return err
+	}
+	message.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoMessages) List(ctx context.Context, filter MessageFilter) ([]models.Message, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (s *mongoMessages) Count(ctx context.Context, conversationID primitive.ObjectID) (int64, error) {
+	return s.coll.CountDocuments(ctx, bson.M{"conversation": conversationID})
+}
+
+func (s *mongoMessages) CountUnread(ctx context.Context, conversationID, userID primitive.ObjectID, since time.Time) (int64, error) {
+	return s.coll.CountDocuments(ctx, bson.M{
+		"conversation": conversationID,
+		"sender":       bson.M{"$ne": userID},
+		"createdAt":    bson.M{"$gt": since},
+	})
+}
+
+func (s *mongoMessages) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Message, error) {
+	var message models.Message
+	err := s.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&message)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (s *mongoMessages) SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error {
+	update := bson.M{"$unset": bson.M{"hidden": ""}}
+	if hidden != nil {
+		update = bson.M{"$set": bson.M{"hidden": hidden}}
+	}
+	result, err := s.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
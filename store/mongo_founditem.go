@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoFoundItems struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoFoundItems) Insert(ctx context.Context, item *models.FoundItem) error {
+	result, err := s.coll.InsertOne(ctx, item)
+	if err != nil {
+		return err
+	}
+	item.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// listNear runs the filter behind a $geoNear stage so results come back
+// nearest first with their distance attached.
+func (s *mongoFoundItems) listNear(ctx context.Context, filter FoundItemFilter) ([]models.FoundItem, error) {
+	pipeline := mongo.Pipeline{filter.Near.stage(filter.query())}
+	if filter.Skip > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: filter.Skip}})
+	}
+	if filter.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: filter.Limit}})
+	}
+
+	cursor, err := s.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		models.FoundItem `bson:",inline"`
+		Distance         float64 `bson:"distance"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.FoundItem, len(results))
+	for i, result := range results {
+		items[i] = result.FoundItem
+		items[i].Distance = &result.Distance
+	}
+	return items, nil
+}
+
+// listText runs a $text search, most relevant first, with each item's
+// score attached.
+func (s *mongoFoundItems) listText(ctx context.Context, filter FoundItemFilter) ([]models.FoundItem, error) {
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": textScore}).
+		SetSort(bson.D{{Key: "score", Value: textScore}, {Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		findOptions.SetLimit(filter.Limit)
+	}
+	if filter.Skip > 0 {
+		findOptions.SetSkip(filter.Skip)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		models.FoundItem `bson:",inline"`
+		Score            float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.FoundItem, len(results))
+	for i, result := range results {
+		items[i] = result.FoundItem
+		items[i].Score = &result.Score
+	}
+	return items, nil
+}
+
+func (s *mongoFoundItems) Count(ctx context.Context, filter FoundItemFilter) (int64, error) {
+	filter.After = nil
+	query := filter.query()
+	if filter.Near != nil {
+		query[foundItemPosition] = filter.Near.within()
+	}
+	return s.coll.CountDocuments(ctx, query)
+}
+
+func (s *mongoFoundItems) FindByID(ctx context.Context, id primitive.ObjectID) (*models.FoundItem, error) {
+	var item models.FoundItem
+	err := s.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *mongoFoundItems) List(ctx context.Context, filter FoundItemFilter) ([]models.FoundItem, error) {
+	if filter.Near != nil {
+		return s.listNear(ctx, filter)
+	}
+	if filter.Text != nil {
+		return s.listText(ctx, filter)
+	}
+
+	findOptions := options.Find().SetSort(foundItemSorts.bson(filter.Sort))
+	if filter.Limit > 0 {
+		findOptions.SetLimit(filter.Limit)
+	}
+	if filter.Skip > 0 {
+		findOptions.SetSkip(filter.Skip)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []models.FoundItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *mongoFoundItems) UpdateOwned(ctx context.Context, id, owner primitive.ObjectID, update FoundItemUpdate) (*models.FoundItem, error) {
+	var item models.FoundItem
+	err := s.coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, foundItemFoundPerson: owner},
+		bson.M{"$set": update.set(time.Now())},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *mongoFoundItems) SetImages(ctx context.Context, id, owner primitive.ObjectID, version int, images []models.Image) (*models.FoundItem, error) {
+	owned := bson.M{"_id": id, foundItemFoundPerson: owner}
+	var item models.FoundItem
+	item.SetImages(images)
+	err := s.coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, foundItemFoundPerson: owner, foundItemImagesVersion: atVersion(version)},
+		bson.M{"$set": bson.M{
+			foundItemImages:         item.Images,
+			foundItemImage:          item.Image,
+			foundItemImageMedium:    item.ImageMedium,
+			foundItemImageThumbnail: item.ImageThumbnail,
+			foundItemImagesVersion:  version + 1,
+			foundItemUpdatedAt:      time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, versionMissed(ctx, s.coll, owned)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *mongoFoundItems) SetFound(ctx context.Context, id primitive.ObjectID, found bool, lostItem, lostPerson primitive.ObjectID) error {
+	set := bson.M{foundItemFound: found}
+	if !lostItem.IsZero() {
+		set[foundItemLostItem] = lostItem
+	}
+	if !lostPerson.IsZero() {
+		set[foundItemLostPerson] = lostPerson
+	}
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoFoundItems) DeleteOwned(ctx context.Context, id, owner primitive.ObjectID) (*models.FoundItem, error) {
+	var item models.FoundItem
+	err := s.coll.FindOneAndDelete(ctx, bson.M{"_id": id, foundItemFoundPerson: owner}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *mongoFoundItems) SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error {
+	return setOrUnset(ctx, s.coll, id, "hidden", hidden)
+}
+
+func (s *mongoFoundItems) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoFoundItems) Images(ctx context.Context) ([]models.Image, error) {
+	cursor, err := s.coll.Find(ctx, bson.M{}, options.Find().SetProjection(imageFields))
+	if err != nil {
+		return nil, err
+	}
+	var items []models.FoundItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	var images []models.Image
+	for i := range items {
+		images = append(images, items[i].ImageList()...)
+	}
+	return images, nil
+}
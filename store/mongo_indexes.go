@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"lostfound-backend/db"
+	"lostfound-backend/models"
+	"lostfound-backend/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the indexes the Mongo stores rely on. Creating an
+// index that already exists with the same options is a no-op.
+func EnsureIndexes(ctx context.Context) error {
+	indexes := map[string][]mongo.IndexModel{
+		// $geoNear needs a 2dsphere index on the position field, and $text
+		// needs the collection's one text index. The rest back the list
+		// filters and ownership checks
+		"lostitems": {{
+			Keys:    bson.D{{Key: "position", Value: "2dsphere"}},
+			Options: options.Index().SetName("position_2dsphere"),
+		}, {
+			Keys:    bson.D{{Key: lostItemCreatedBy, Value: 1}},
+			Options: options.Index().SetName(lostItemCreatedBy),
+		}, {
+			Keys:    bson.D{{Key: lostItemCategory, Value: 1}},
+			Options: options.Index().SetName(lostItemCategory),
+		}, {
+			Keys:    bson.D{{Key: lostItemDistrict, Value: 1}},
+			Options: options.Index().SetName(lostItemDistrict),
+		}, {
+			Keys:    bson.D{{Key: lostItemDateLost, Value: -1}},
+			Options: options.Index().SetName(lostItemDateLost),
+		}, {
+			Keys:    newestFirst(lostItemCreatedAt),
+			Options: options.Index().SetName(lostItemCreatedAt + "__id"),
+		}, {
+			Keys: bson.D{
+				{Key: "name", Value: "text"},
+				{Key: "category", Value: "text"},
+				{Key: "locations", Value: "text"},
+				{Key: "description", Value: "text"},
+			},
+			Options: textIndexOptions(bson.D{
+				{Key: "name", Value: nameWeight},
+				{Key: "category", Value: categoryWeight},
+				{Key: "locations", Value: locationWeight},
+				{Key: "description", Value: descriptionWeight},
+			}),
+		}},
+		"founditems": {{
+			Keys:    bson.D{{Key: "position", Value: "2dsphere"}},
+			Options: options.Index().SetName("position_2dsphere"),
+		}, {
+			Keys:    bson.D{{Key: foundItemFoundPerson, Value: 1}},
+			Options: options.Index().SetName(foundItemFoundPerson),
+		}, {
+			Keys:    bson.D{{Key: foundItemLostPerson, Value: 1}},
+			Options: options.Index().SetName(foundItemLostPerson).SetSparse(true),
+		}, {
+			Keys:    bson.D{{Key: foundItemLostItem, Value: 1}},
+			Options: options.Index().SetName(foundItemLostItem).SetSparse(true),
+		}, {
+			Keys:    newestFirst(foundItemCreatedAt),
+			Options: options.Index().SetName(foundItemCreatedAt + "__id"),
+		}, {
+			Keys: bson.D{
+				{Key: "name", Value: "text"},
+				{Key: "category", Value: "text"},
+				{Key: "locationFound", Value: "text"},
+				{Key: "description", Value: "text"},
+			},
+			Options: textIndexOptions(bson.D{
+				{Key: "name", Value: nameWeight},
+				{Key: "category", Value: categoryWeight},
+				{Key: "locationFound", Value: locationWeight},
+				{Key: "description", Value: descriptionWeight},
+			}),
+		}},
+		// Signup relies on the unique email to reject taken addresses
+		"users": {{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("email").SetUnique(true),
+		}},
+		// A lost item is bookmarked at most once per user, and each user's
+		// bookmarks are listed newest first
+		"bookmarks": {{
+			Keys:    bson.D{{Key: "user", Value: 1}, {Key: "lostItem", Value: 1}},
+			Options: options.Index().SetName("user_lostItem").SetUnique(true),
+		}, {
+			Keys:    append(bson.D{{Key: "user", Value: 1}}, newestFirst("createdAt")...),
+			Options: options.Index().SetName("user_createdAt__id"),
+		}},
+		// Listing and counting a user's notifications, newest first
+		"notifications": {{
+			Keys:    bson.D{{Key: "user", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("user_createdAt"),
+		}},
+		// One conversation per item pair, listed per participant
+		"conversations": {{
+			Keys:    bson.D{{Key: "lostItem", Value: 1}, {Key: "foundItem", Value: 1}},
+			Options: options.Index().SetName("lostItem_foundItem").SetUnique(true),
+		}, {
+			Keys:    append(bson.D{{Key: "owner", Value: 1}}, newestFirst("createdAt")...),
+			Options: options.Index().SetName("owner_createdAt__id"),
+		}, {
+			Keys:    append(bson.D{{Key: "finder", Value: 1}}, newestFirst("createdAt")...),
+			Options: options.Index().SetName("finder_createdAt__id"),
+		}},
+		// A found item's claims and a user's own claims, newest first, and
+		// at most one approved claim per found item
+		"claims": {{
+			Keys:    append(bson.D{{Key: "foundItem", Value: 1}}, newestFirst("createdAt")...),
+			Options: options.Index().SetName("foundItem_createdAt__id"),
+		}, {
+			Keys:    append(bson.D{{Key: "claimant", Value: 1}}, newestFirst("createdAt")...),
+			Options: options.Index().SetName("claimant_createdAt__id"),
+		}, {
+			Keys: bson.D{{Key: "foundItem", Value: 1}},
+			Options: options.Index().SetName("approved_foundItem").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": models.ClaimApproved}),
+		}},
+		// An item's matches, best score first
+		"matches": {{
+			Keys:    bson.D{{Key: "lostItem", Value: 1}, {Key: "score", Value: -1}},
+			Options: options.Index().SetName("lostItem_score"),
+		}, {
+			Keys:    bson.D{{Key: "foundItem", Value: 1}, {Key: "score", Value: -1}},
+			Options: options.Index().SetName("foundItem_score"),
+		}},
+		"messages": {{
+			Keys:    bson.D{{Key: "conversation", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("conversation_createdAt"),
+		}},
+		// Expired tokens and revocations are removed by MongoDB's TTL monitor
+		"refreshtokens": {{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetName("tokenHash").SetUnique(true),
+		}, {
+			Keys:    bson.D{{Key: "family", Value: 1}},
+			Options: options.Index().SetName("family"),
+		}, {
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+		}},
+		"revokedtokens": {{
+			Keys:    bson.D{{Key: "jti", Value: 1}},
+			Options: options.Index().SetName("jti").SetSparse(true),
+		}, {
+			Keys:    bson.D{{Key: "family", Value: 1}},
+			Options: options.Index().SetName("family").SetSparse(true),
+		}, {
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+		}},
+		// The moderation queue, and one open report per reporter and target
+		"reports": {{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("status_createdAt"),
+		}, {
+			Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "target", Value: 1}, {Key: "reporter", Value: 1}},
+			Options: options.Index().SetName("open_target_reporter").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": models.ReportOpen}),
+		}},
+		"auditlog": {{
+			Keys:    bson.D{{Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("createdAt"),
+		}, {
+			Keys:    bson.D{{Key: "target", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("target_createdAt"),
+		}, {
+			Keys:    bson.D{{Key: "actor", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("actor_createdAt"),
+		}},
+		"onetimetokens": {{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetName("tokenHash").SetUnique(true),
+		}, {
+			Keys:    bson.D{{Key: "user", Value: 1}, {Key: "purpose", Value: 1}},
+			Options: options.Index().SetName("user_purpose"),
+		}, {
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+		}},
+	}
+
+	for collection, models := range indexes {
+		if _, err := db.GetCollection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("create indexes on %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+func textIndexOptions(weights bson.D) *options.IndexOptions {
+	return options.Index().
+		SetName("text_search").
+		SetWeights(weights).
+		SetDefaultLanguage(textsearch.Language).
+		// Items have no per-document language; keep the default override
+		// field name from ever being read off user data
+		SetLanguageOverride("textLanguage")
+}
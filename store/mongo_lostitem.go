@@ -0,0 +1,292 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoLostItems struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoLostItems) Insert(ctx context.Context, item *models.LostItem) error {
+	result, err := s.coll.InsertOne(ctx, item)
+	if err != nil {
+		return err
+	}
+	item.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// listNear runs the filter behind a $geoNear stage so results come back
+// nearest first with their distance attached.
+func (s *mongoLostItems) listNear(ctx context.Context, filter LostItemFilter) ([]models.LostItem, error) {
+	pipeline := mongo.Pipeline{filter.Near.stage(filter.query())}
+	if filter.Skip > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: filter.Skip}})
+	}
+	if filter.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: filter.Limit}})
+	}
+
+	cursor, err := s.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		models.LostItem `bson:",inline"`
+		Distance        float64 `bson:"distance"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.LostItem, len(results))
+	for i, result := range results {
+		items[i] = result.LostItem
+		items[i].Distance = &result.Distance
+	}
+	return items, nil
+}
+
+// listText runs a $text search, most relevant first, with each item's
+// score attached.
+func (s *mongoLostItems) listText(ctx context.Context, filter LostItemFilter) ([]models.LostItem, error) {
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": textScore}).
+		SetSort(bson.D{{Key: "score", Value: textScore}, {Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		findOptions.SetLimit(filter.Limit)
+	}
+	if filter.Skip > 0 {
+		findOptions.SetSkip(filter.Skip)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		models.LostItem `bson:",inline"`
+		Score           float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.LostItem, len(results))
+	for i, result := range results {
+		items[i] = result.LostItem
+		items[i].Score = &result.Score
+	}
+	return items, nil
+}
+
+func (s *mongoLostItems) Count(ctx context.Context, filter LostItemFilter) (int64, error) {
+	filter.After = nil
+	query := filter.query()
+	if filter.Near != nil {
+		query[lostItemPosition] = filter.Near.within()
+	}
+	return s.coll.CountDocuments(ctx, query)
+}
+
+func (s *mongoLostItems) FindByID(ctx context.Context, id primitive.ObjectID) (*models.LostItem, error) {
+	var item models.LostItem
+	err := s.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *mongoLostItems) List(ctx context.Context, filter LostItemFilter) ([]models.LostItem, error) {
+	if filter.Near != nil {
+		return s.listNear(ctx, filter)
+	}
+	if filter.Text != nil {
+		return s.listText(ctx, filter)
+	}
+
+	findOptions := options.Find().SetSort(lostItemSorts.bson(filter.Sort))
+	if filter.Limit > 0 {
+		findOptions.SetLimit(filter.Limit)
+	}
+	if filter.Skip > 0 {
+		findOptions.SetSkip(filter.Skip)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []models.LostItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *mongoLostItems) Distinct(ctx context.Context, field string) ([]string, error) {
+	values, err := s.coll.Distinct(ctx, field, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	return distinctStrings(values), nil
+}
+
+func (s *mongoLostItems) UpdateOwned(ctx context.Context, id, owner primitive.ObjectID, update LostItemUpdate) (*models.LostItem, error) {
+	owned := bson.M{"_id": id, lostItemCreatedBy: owner}
+	filter := bson.M{"_id": id, lostItemCreatedBy: owner}
+	if update.Images != nil {
+		filter[lostItemImagesVersion] = atVersion(update.ImagesVersion)
+	}
+	var item models.LostItem
+	err := s.coll.FindOneAndUpdate(ctx,
+		filter,
+		bson.M{"$set": update.set(time.Now())},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		if update.Images != nil {
+			return nil, versionMissed(ctx, s.coll, owned)
+		}
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *mongoLostItems) SetStatus(ctx context.Context, id primitive.ObjectID, change models.LostItemStatusChange) error {
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id, lostItemStatus: statusIn(change.From)},
+		bson.M{
+			"$set":  bson.M{lostItemStatus: change.To, lostItemUpdatedAt: change.At},
+			"$push": bson.M{lostItemStatusHistory: change},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoLostItems) ExpireStale(ctx context.Context, cutoff time.Time) (int64, error) {
+	now := time.Now()
+	change := models.LostItemStatusChange{
+		From: models.LostItemOpen,
+		To:   models.LostItemExpired,
+		At:   now,
+		Note: "No activity before expiry",
+	}
+	result, err := s.coll.UpdateMany(ctx,
+		bson.M{lostItemStatus: statusIn(models.LostItemOpen), lostItemDateLost: bson.M{"$lt": cutoff}},
+		bson.M{
+			"$set":  bson.M{lostItemStatus: models.LostItemExpired, lostItemUpdatedAt: now},
+			"$push": bson.M{lostItemStatusHistory: change},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (s *mongoLostItems) DeleteOwned(ctx context.Context, id, owner primitive.ObjectID) (*models.LostItem, error) {
+	var item models.LostItem
+	err := s.coll.FindOneAndDelete(ctx, bson.M{"_id": id, lostItemCreatedBy: owner}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// distinctStrings keeps the string values of a Distinct result, skipping
+// nulls and any documents that stored the field with another type.
+func distinctStrings(values []interface{}) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (s *mongoLostItems) SetHidden(ctx context.Context, id primitive.ObjectID, hidden *models.ModerationAction) error {
+	return setOrUnset(ctx, s.coll, id, "hidden", hidden)
+}
+
+func (s *mongoLostItems) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoLostItems) Images(ctx context.Context) ([]models.Image, error) {
+	cursor, err := s.coll.Find(ctx, bson.M{}, options.Find().SetProjection(imageFields))
+	if err != nil {
+		return nil, err
+	}
+	var items []models.LostItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	var images []models.Image
+	for i := range items {
+		images = append(images, items[i].ImageList()...)
+	}
+	return images, nil
+}
+
+// imageFields projects the image fields shared by lost and found items.
+var imageFields = bson.M{"images": 1, "image": 1, "imageMedium": 1, "imageThumbnail": 1}
+
+// setOrUnset sets field on the document to value, or removes the field when
+// value is a nil pointer, bumping updatedAt either way.
+func setOrUnset[T any](ctx context.Context, coll *mongo.Collection, id primitive.ObjectID, field string, value *T) error {
+	set := bson.M{"updatedAt": time.Now()}
+	update := bson.M{"$set": set}
+	if value != nil {
+		set[field] = value
+	} else {
+		update["$unset"] = bson.M{field: ""}
+	}
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoMatches struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoMatches) Upsert(ctx context.Context, match *models.Match) error {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := s.coll.FindOneAndUpdate(ctx,
+		bson.M{"lostItem": match.LostItem, "foundItem": match.FoundItem},
+		bson.M{
+			"$set": bson.M{
+				"score":     match.Score,
+				"breakdown": match.Breakdown,
+				"updatedAt": now,
+			},
+			"$setOnInsert": bson.M{"createdAt": now},
+		},
+		opts,
+	).Decode(match)
+	return err
+}
+
+func (s *mongoMatches) List(ctx context.Context, filter MatchFilter) ([]models.Match, error) {
+	if filter.Viewer != nil {
+		return s.listVisible(ctx, filter)
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	if filter.Skip > 0 {
+		opts.SetSkip(filter.Skip)
+	}
+	cursor, err := s.coll.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var matches []models.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func (s *mongoMatches) ListByLostItem(ctx context.Context, lostItemID primitive.ObjectID) ([]models.Match, error) {
+	return s.List(ctx, MatchFilter{LostItem: lostItemID})
+}
+
+func (s *mongoMatches) ListByFoundItem(ctx context.Context, foundItemID primitive.ObjectID) ([]models.Match, error) {
+	return s.List(ctx, MatchFilter{FoundItem: foundItemID})
+}
+
+func (s *mongoMatches) Count(ctx context.Context, filter MatchFilter) (int64, error) {
+	if filter.Viewer == nil {
+		return s.coll.CountDocuments(ctx, filter.query())
+	}
+	pipeline := append(visiblePipeline(filter), bson.D{{Key: "$count", Value: "n"}})
+	cursor, err := s.coll.Aggregate(ctx, pipeline, options.Aggregate().SetMaxTime(5*time.Second))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		N int64 `bson:"n"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].N, nil
+}
+
+// visiblePipeline selects the matches of filter its viewer may see, by
+// looking up the other item of each match.
+func visiblePipeline(filter MatchFilter) mongo.Pipeline {
+	collection, matchField, _ := filter.other()
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter.query()}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: collection},
+			{Key: "localField", Value: matchField},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "item"},
+		}}},
+		{{Key: "$match", Value: filter.viewerQuery()}},
+	}
+}
+
+// listVisible is List for a filter with a viewer: the lookup runs before
+// the sort and page, so hidden and deleted items never take up a slot.
+func (s *mongoMatches) listVisible(ctx context.Context, filter MatchFilter) ([]models.Match, error) {
+	pipeline := append(visiblePipeline(filter),
+		bson.D{{Key: "$project", Value: bson.M{"item": 0}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}}},
+	)
+	if filter.Skip > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: filter.Skip}})
+	}
+	if filter.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: filter.Limit}})
+	}
+	cursor, err := s.coll.Aggregate(ctx, pipeline, options.Aggregate().SetMaxTime(5*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var matches []models.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func (s *mongoMatches) DeleteByLostItem(ctx context.Context, lostItemID primitive.ObjectID) error {
+	_, err := s.coll.DeleteMany(ctx, bson.M{"lostItem": lostItemID})
+	return err
+}
+
+func (s *mongoMatches) DeleteByFoundItem(ctx context.Context, foundItemID primitive.ObjectID) error {
+	_, err := s.coll.DeleteMany(ctx, bson.M{"foundItem": foundItemID})
+	return err
+}
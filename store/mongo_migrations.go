@@ -0,0 +1,327 @@
+package store
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"lostfound-backend/db"
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration is a versioned change to the stored documents. Migrations run
+// once each, in version order. Up returns how many documents it changed and
+// must be safe to run again, since a run interrupted before the migration was
+// recorded starts it over.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context) (int64, error)
+}
+
+// AppliedMigration is a migration as recorded in the migrations collection.
+type AppliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	Changed     int64     `bson:"changed"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// migrations lists every migration, oldest first. Append new ones with the
+// next version; never renumber or remove one that has shipped.
+var migrations = []Migration{
+	{1, "Move notifications embedded in users to their own collection", moveEmbeddedNotifications},
+	{2, "Rename lostitems.user to createdBy and founditems.foundBy to foundPerson", renameOwnerFields},
+	{3, "Link found items to the lost items they answer", linkFoundItemsToLostItems},
+	{4, "Fill in createdAt of items and bookmarks from their IDs", backfillCreatedAt},
+}
+
+func migrationsCollection() *mongo.Collection {
+	return db.GetCollection("migrations")
+}
+
+// AppliedMigrations returns the migrations recorded as applied, oldest first.
+func AppliedMigrations(ctx context.Context) ([]AppliedMigration, error) {
+	cursor, err := migrationsCollection().Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var applied []AppliedMigration
+	if err := cursor.All(ctx, &applied); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// PendingMigrations returns the migrations not applied yet, in the order
+// Migrate would run them.
+func PendingMigrations(ctx context.Context) ([]Migration, error) {
+	applied, err := AppliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int]bool, len(applied))
+	for _, m := range applied {
+		done[m.Version] = true
+	}
+	var pending []Migration
+	for _, m := range migrations {
+		if !done[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate runs the pending migrations and records each one as it finishes.
+// It stops at the first failure, leaving the later ones pending, and returns
+// the migrations applied so far.
+func Migrate(ctx context.Context) ([]AppliedMigration, error) {
+	pending, err := PendingMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var applied []AppliedMigration
+	for _, m := range pending {
+		changed, err := m.Up(ctx)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, err)
+		}
+		record := AppliedMigration{
+			Version:     m.Version,
+			Description: m.Description,
+			Changed:     changed,
+			AppliedAt:   time.Now(),
+		}
+		// Another instance may have finished the same migration meanwhile
+		if _, err := migrationsCollection().ReplaceOne(ctx,
+			bson.M{"_id": m.Version}, record, options.Replace().SetUpsert(true),
+		); err != nil {
+			return applied, fmt.Errorf("record migration %d: %w", m.Version, err)
+		}
+		applied = append(applied, record)
+	}
+	return applied, nil
+}
+
+// moveEmbeddedNotifications moves the notifications array that used to be
+// embedded in user documents into the notifications collection, and returns
+// how many were moved. Moved notifications get IDs derived from their user
+// and position, so a run interrupted before a user's array was unset can be
+// repeated without creating duplicates.
+func moveEmbeddedNotifications(ctx context.Context) (int64, error) {
+	users := db.GetCollection("users")
+	notifications := db.GetCollection("notifications")
+
+	cursor, err := users.Find(ctx,
+		bson.M{"notifications": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"notifications": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var moved int64
+	for cursor.Next(ctx) {
+		var user struct {
+			ID            primitive.ObjectID `bson:"_id"`
+			Notifications []struct {
+				Message   string    `bson:"message"`
+				Read      bool      `bson:"read"`
+				CreatedAt time.Time `bson:"createdAt"`
+			} `bson:"notifications"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return moved, err
+		}
+
+		docs := make([]interface{}, 0, len(user.Notifications))
+		for i, old := range user.Notifications {
+			createdAt := old.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = user.ID.Timestamp()
+			}
+			docs = append(docs, models.Notification{
+				ID:        embeddedNotificationID(user.ID, i, createdAt),
+				User:      user.ID,
+				Type:      models.NotificationGeneral,
+				Message:   old.Message,
+				Read:      old.Read,
+				CreatedAt: createdAt,
+			})
+		}
+		if len(docs) > 0 {
+			_, err := notifications.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+			if err != nil && !onlyDuplicateKeys(err) {
+				return moved, fmt.Errorf("move notifications of user %s: %w", user.ID.Hex(), err)
+			}
+		}
+
+		if _, err := users.UpdateOne(ctx,
+			bson.M{"_id": user.ID},
+			bson.M{"$unset": bson.M{"notifications": ""}},
+		); err != nil {
+			return moved, err
+		}
+		moved += int64(len(docs))
+	}
+	return moved, cursor.Err()
+}
+
+// linkFoundItemsToLostItems fills in the lostItem field of found items
+// reported before it existed, and returns how many were linked. Some clients
+// stored the lost item's ID in lostPerson, which is replaced by the item's
+// owner; for the rest the lost item named by an approved claim is used.
+// Items with no known lost item are left alone.
+func linkFoundItemsToLostItems(ctx context.Context) (int64, error) {
+	foundItems := db.GetCollection("founditems")
+	lostItems := db.GetCollection("lostitems")
+	claims := db.GetCollection("claims")
+
+	cursor, err := foundItems.Find(ctx,
+		bson.M{foundItemLostItem: bson.M{"$exists": false}, foundItemLostPerson: bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{foundItemLostPerson: 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var linked int64
+	for cursor.Next(ctx) {
+		var item models.FoundItem
+		if err := cursor.Decode(&item); err != nil {
+			return linked, err
+		}
+
+		set := bson.M{}
+		var lostItem models.LostItem
+		err := lostItems.FindOne(ctx,
+			bson.M{"_id": item.LostPerson},
+			options.FindOne().SetProjection(bson.M{lostItemCreatedBy: 1}),
+		).Decode(&lostItem)
+		switch {
+		case err == nil:
+			set[foundItemLostItem] = lostItem.ID
+			set[foundItemLostPerson] = lostItem.CreatedBy
+		case err == mongo.ErrNoDocuments:
+			var claim models.Claim
+			err := claims.FindOne(ctx, bson.M{
+				"foundItem": item.ID,
+				"status":    models.ClaimApproved,
+				"lostItem":  bson.M{"$exists": true},
+			}).Decode(&claim)
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			if err != nil {
+				return linked, err
+			}
+			set[foundItemLostItem] = claim.LostItem
+		default:
+			return linked, err
+		}
+
+		if _, err := foundItems.UpdateOne(ctx, bson.M{"_id": item.ID}, bson.M{"$set": set}); err != nil {
+			return linked, fmt.Errorf("link found item %s: %w", item.ID.Hex(), err)
+		}
+		linked++
+	}
+	return linked, cursor.Err()
+}
+
+// renameOwnerFields moves the owner of lost and found items to the fields
+// the models use. Older documents stored it as lostitems.user and
+// founditems.foundBy, which ownership checks on the current names never
+// matched. Where both names are present the current one wins.
+func renameOwnerFields(ctx context.Context) (int64, error) {
+	renames := []struct {
+		collection, from, to string
+	}{
+		{"lostitems", "user", lostItemCreatedBy},
+		{"founditems", "foundBy", foundItemFoundPerson},
+	}
+	var changed int64
+	for _, r := range renames {
+		coll := db.GetCollection(r.collection)
+		result, err := coll.UpdateMany(ctx,
+			bson.M{r.from: bson.M{"$exists": true}, r.to: bson.M{"$exists": false}},
+			bson.M{"$rename": bson.M{r.from: r.to}},
+		)
+		if err != nil {
+			return changed, fmt.Errorf("rename %s.%s: %w", r.collection, r.from, err)
+		}
+		changed += result.ModifiedCount
+
+		result, err = coll.UpdateMany(ctx,
+			bson.M{r.from: bson.M{"$exists": true}},
+			bson.M{"$unset": bson.M{r.from: ""}},
+		)
+		if err != nil {
+			return changed, fmt.Errorf("unset %s.%s: %w", r.collection, r.from, err)
+		}
+		changed += result.ModifiedCount
+	}
+	return changed, nil
+}
+
+// backfillCreatedAt sets createdAt on the lost items, found items and
+// bookmarks stored without one, which cursor pagination would otherwise
+// lump together at the end of every listing. Found items were never given a
+// creation time before. The time is taken from the ObjectID, which records
+// when the document was inserted.
+func backfillCreatedAt(ctx context.Context) (int64, error) {
+	fields := []struct {
+		collection, createdAt string
+	}{
+		{"lostitems", lostItemCreatedAt},
+		{"founditems", foundItemCreatedAt},
+		{"bookmarks", "createdAt"},
+	}
+	var changed int64
+	for _, f := range fields {
+		// Matches missing and null values as well as the zero time
+		result, err := db.GetCollection(f.collection).UpdateMany(ctx,
+			bson.M{f.createdAt: bson.M{"$not": bson.M{"$gt": time.Unix(0, 0)}}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{f.createdAt: bson.M{"$toDate": "$_id"}}}}},
+		)
+		if err != nil {
+			return changed, fmt.Errorf("backfill %s.%s: %w", f.collection, f.createdAt, err)
+		}
+		changed += result.ModifiedCount
+	}
+	return changed, nil
+}
+
+// embeddedNotificationID builds a stable ObjectID for the index'th embedded
+// notification of userID. The leading timestamp keeps the IDs in creation order.
+func embeddedNotificationID(userID primitive.ObjectID, index int, createdAt time.Time) primitive.ObjectID {
+	sum := sha1.Sum(fmt.Appendf(nil, "%s/%d", userID.Hex(), index))
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[:4], uint32(createdAt.Unix()))
+	copy(id[4:], sum[:8])
+	return id
+}
+
+// onlyDuplicateKeys reports whether every write error in err is a duplicate key.
+func onlyDuplicateKeys(err error) bool {
+	var bulk mongo.BulkWriteException
+	if !errors.As(err, &bulk) || bulk.WriteConcernError != nil || len(bulk.WriteErrors) == 0 {
+		return false
+	}
+	for _, e := range bulk.WriteErrors {
+		if e.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
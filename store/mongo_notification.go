@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoNotifications struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoNotifications) Insert(ctx context.Context, n *models.Notification) error {
+	result, err := s.coll.InsertOne(ctx, n)
+	if err != nil {
+		return err
+	}
+	n.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoNotifications) List(ctx context.Context, filter NotificationFilter) ([]models.Notification, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []models.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (s *mongoNotifications) Count(ctx context.Context, filter NotificationFilter) (int64, error) {
+	filter.After = nil
+	return s.coll.CountDocuments(ctx, filter.query())
+}
+
+func (s *mongoNotifications) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.coll.CountDocuments(ctx, bson.M{"user": userID, "read": false})
+}
+
+func (s *mongoNotifications) MarkRead(ctx context.Context, id, userID primitive.ObjectID) error {
+	// Only stamp readAt the first time
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id, "user": userID},
+		bson.A{bson.M{"$set": bson.M{
+			"read":   true,
+			"readAt": bson.M{"$ifNull": bson.A{"$readAt", time.Now()}},
+		}}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoNotifications) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := s.coll.UpdateMany(ctx,
+		bson.M{"user": userID, "read": false},
+		bson.M{"$set": bson.M{"read": true, "readAt": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (s *mongoNotifications) DeleteOwned(ctx context.Context, id, userID primitive.ObjectID) error {
+	result, err := s.coll.DeleteOne(ctx, bson.M{"_id": id, "user": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
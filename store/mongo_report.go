@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoReports struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoReports) Insert(ctx context.Context, report *models.Report) error {
+	result, err := s.coll.InsertOne(ctx, report)
+	if err != nil {
+		return err
+	}
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoReports) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Report, error) {
+	var report models.Report
+	err := s.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&report)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (s *mongoReports) List(ctx context.Context, filter ReportFilter) ([]models.Report, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	if filter.Skip > 0 {
+		opts.SetSkip(filter.Skip)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reports []models.Report
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (s *mongoReports) Count(ctx context.Context, filter ReportFilter) (int64, error) {
+	return s.coll.CountDocuments(ctx, filter.query())
+}
+
+func (s *mongoReports) CountOpen(ctx context.Context, targetType models.TargetType, target primitive.ObjectID) (int64, error) {
+	return s.coll.CountDocuments(ctx, bson.M{
+		"targetType": targetType,
+		"target":     target,
+		"status":     models.ReportOpen,
+	})
+}
+
+func (s *mongoReports) Close(ctx context.Context, targetType models.TargetType, target primitive.ObjectID, status models.ReportStatus, decision models.ModerationAction) (int64, error) {
+	result, err := s.coll.UpdateMany(ctx,
+		bson.M{"targetType": targetType, "target": target, "status": models.ReportOpen},
+		bson.M{"$set": bson.M{"status": status, "decision": decision}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+type mongoAuditLog struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoAuditLog) Insert(ctx context.Context, entry *models.AuditEntry) error {
+	result, err := s.coll.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoAuditLog) List(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := s.coll.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *mongoAuditLog) Count(ctx context.Context, filter AuditFilter) (int64, error) {
+	filter.After = nil
+	return s.coll.CountDocuments(ctx, filter.query())
+}
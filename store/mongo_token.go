@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRefreshTokens struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoRefreshTokens) Insert(ctx context.Context, token *models.RefreshToken) error {
+	result, err := s.coll.InsertOne(ctx, token)
+	if err != nil {
+		return err
+	}
+	token.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoRefreshTokens) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := s.coll.FindOne(ctx, bson.M{"tokenHash": hash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *mongoRefreshTokens) Rotate(ctx context.Context, id primitive.ObjectID, next *models.RefreshToken) error {
+	if next.ID.IsZero() {
+		next.ID = primitive.NewObjectID()
+	}
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{
+			"_id":       id,
+			"usedAt":    bson.M{"$exists": false},
+			"revokedAt": bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"usedAt": next.CreatedAt, "replacedBy": next.ID}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return s.Insert(ctx, next)
+}
+
+func (s *mongoRefreshTokens) RevokeFamily(ctx context.Context, family primitive.ObjectID, at time.Time) error {
+	_, err := s.coll.UpdateMany(ctx,
+		bson.M{"family": family, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": at}},
+	)
+	return err
+}
+
+type mongoRevokedTokens struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoRevokedTokens) Revoke(ctx context.Context, revoked *models.RevokedToken) error {
+	result, err := s.coll.InsertOne(ctx, revoked)
+	if err != nil {
+		return err
+	}
+	revoked.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoRevokedTokens) IsRevoked(ctx context.Context, jti string, family primitive.ObjectID) (bool, error) {
+	or := bson.A{bson.M{"jti": jti}}
+	if !family.IsZero() {
+		or = append(or, bson.M{"family": family})
+	}
+	// The TTL monitor only runs once a minute, so filter on expiry as well
+	count, err := s.coll.CountDocuments(ctx, bson.M{
+		"$or":       or,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *mongoRefreshTokens) RevokeUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error) {
+	filter := bson.M{"user": userID, "revokedAt": bson.M{"$exists": false}}
+	values, err := s.coll.Distinct(ctx, "family", filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.coll.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revokedAt": at}}); err != nil {
+		return nil, err
+	}
+	families := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			families = append(families, id)
+		}
+	}
+	return families, nil
+}
+
+type mongoOneTimeTokens struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoOneTimeTokens) Insert(ctx context.Context, token *models.OneTimeToken) error {
+	result, err := s.coll.InsertOne(ctx, token)
+	if err != nil {
+		return err
+	}
+	token.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoOneTimeTokens) Consume(ctx context.Context, purpose models.TokenPurpose, hash string, at time.Time) (*models.OneTimeToken, error) {
+	var token models.OneTimeToken
+	err := s.coll.FindOneAndUpdate(ctx,
+		bson.M{
+			"purpose":   purpose,
+			"tokenHash": hash,
+			"usedAt":    bson.M{"$exists": false},
+			"expiresAt": bson.M{"$gt": at},
+		},
+		bson.M{"$set": bson.M{"usedAt": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *mongoOneTimeTokens) DeleteForUser(ctx context.Context, userID primitive.ObjectID, purpose models.TokenPurpose) error {
+	_, err := s.coll.DeleteMany(ctx, bson.M{"user": userID, "purpose": purpose})
+	return err
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoUsers struct {
+	coll *mongo.Collection
+}
+
+func (s *mongoUsers) Insert(ctx context.Context, user *models.User) error {
+	result, err := s.coll.InsertOne(ctx, user)
+	if err != nil {
+		return err
+	}
+	user.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoUsers) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var user models.User
+	err := s.coll.FindOne(ctx, filter).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *mongoUsers) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	return s.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *mongoUsers) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.findOne(ctx, bson.M{"email": email})
+}
+
+func (s *mongoUsers) UpdateProfile(ctx context.Context, id primitive.ObjectID, update ProfileUpdate) error {
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"username":          update.Username,
+			"phone":             update.Phone,
+			"profession":        update.Profession,
+			"location.district": update.District,
+			"location.state":    update.State,
+			"updatedAt":         time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoUsers) SetPassword(ctx context.Context, id primitive.ObjectID, hash string) error {
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"password": hash, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoUsers) SetPendingEmail(ctx context.Context, id primitive.ObjectID, email string) error {
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"pendingEmail": email, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoUsers) VerifyEmail(ctx context.Context, id primitive.ObjectID, email string) error {
+	// Confirming the current address must keep a pending change around, so
+	// pendingEmail is only cleared when it is the address being confirmed
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id, "$or": bson.A{bson.M{"email": email}, bson.M{"pendingEmail": email}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"email":         email,
+			"emailVerified": true,
+			"pendingEmail": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$pendingEmail", email}}, "$$REMOVE", "$pendingEmail",
+			}},
+			"updatedAt": time.Now(),
+		}}}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoUsers) SetRole(ctx context.Context, id primitive.ObjectID, role models.Role) error {
+	result, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"role": role, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoUsers) SetSuspension(ctx context.Context, id primitive.ObjectID, suspension *models.Suspension) error {
+	return setOrUnset(ctx, s.coll, id, "suspension", suspension)
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationFilter selects one user's notifications. Results are newest first.
+type NotificationFilter struct {
+	User       primitive.ObjectID
+	UnreadOnly bool
+	// After continues the listing after the notification the cursor marks.
+	After *pagination.Cursor
+	// Since keeps only the notifications created after the one the cursor
+	// marks, for replaying what a client missed.
+	Since *pagination.Cursor
+	Limit int64
+}
+
+func (f NotificationFilter) query() bson.M {
+	filter := bson.M{"user": f.User}
+	if f.UnreadOnly {
+		filter["read"] = false
+	}
+	keysetAfter(filter, "createdAt", f.After)
+	keysetBefore(filter, "createdAt", f.Since)
+	return filter
+}
+
+// matches is the in-memory equivalent of query.
+func (f NotificationFilter) matches(n *models.Notification) bool {
+	return n.User == f.User && (!f.UnreadOnly || !n.Read) &&
+		isAfter(n.CreatedAt, n.ID, f.After) && isBefore(n.CreatedAt, n.ID, f.Since)
+}
+
+type NotificationStore interface {
+	Insert(ctx context.Context, n *models.Notification) error
+	List(ctx context.Context, filter NotificationFilter) ([]models.Notification, error)
+	// Count returns how many notifications match filter, ignoring its
+	// cursor and limit.
+	Count(ctx context.Context, filter NotificationFilter) (int64, error)
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// MarkRead marks one of the user's notifications read. Marking an
+	// already read notification again is not an error.
+	MarkRead(ctx context.Context, id, userID primitive.ObjectID) error
+	// MarkAllRead marks every unread notification of the user read and
+	// returns how many changed.
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// DeleteOwned removes the notification only if it belongs to userID.
+	DeleteOwned(ctx context.Context, id, userID primitive.ObjectID) error
+}
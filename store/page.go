@@ -0,0 +1,85 @@
+package store
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"lostfound-backend/pagination"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newestFirst is the order keyset cursors page through: by createdAt, with
+// _id breaking ties between items created in the same millisecond.
+func newestFirst(createdAt string) bson.D {
+	return bson.D{{Key: createdAt, Value: -1}, {Key: "_id", Value: -1}}
+}
+
+// keysetAfter adds the condition selecting the items that come after cursor
+// in newestFirst order.
+func keysetAfter(filter bson.M, createdAt string, cursor *pagination.Cursor) {
+	if cursor == nil {
+		return
+	}
+	after := bson.M{"$or": bson.A{
+		bson.M{createdAt: bson.M{"$lt": cursor.CreatedAt}},
+		bson.M{createdAt: cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+	}}
+	if and, ok := filter["$and"].(bson.A); ok {
+		filter["$and"] = append(and, after)
+	} else {
+		filter["$and"] = bson.A{after}
+	}
+}
+
+// keysetBefore adds the condition selecting the items that come before
+// cursor in newestFirst order, i.e. the ones created since.
+func keysetBefore(filter bson.M, createdAt string, cursor *pagination.Cursor) {
+	if cursor == nil {
+		return
+	}
+	before := bson.M{"$or": bson.A{
+		bson.M{createdAt: bson.M{"$gt": cursor.CreatedAt}},
+		bson.M{createdAt: cursor.CreatedAt, "_id": bson.M{"$gt": cursor.ID}},
+	}}
+	if and, ok := filter["$and"].(bson.A); ok {
+		filter["$and"] = append(and, before)
+	} else {
+		filter["$and"] = bson.A{before}
+	}
+}
+
+// isBefore is the in-memory equivalent of keysetBefore.
+func isBefore(createdAt time.Time, id primitive.ObjectID, cursor *pagination.Cursor) bool {
+	if cursor == nil {
+		return true
+	}
+	createdAt = createdAt.Truncate(time.Millisecond)
+	at := cursor.CreatedAt.Truncate(time.Millisecond)
+	return createdAt.After(at) || (createdAt.Equal(at) && id.Hex() > cursor.ID.Hex())
+}
+
+// isAfter is the in-memory equivalent of keysetAfter.
+func isAfter(createdAt time.Time, id primitive.ObjectID, cursor *pagination.Cursor) bool {
+	if cursor == nil {
+		return true
+	}
+	createdAt = createdAt.Truncate(time.Millisecond)
+	at := cursor.CreatedAt.Truncate(time.Millisecond)
+	return createdAt.Before(at) || (createdAt.Equal(at) && id.Hex() < cursor.ID.Hex())
+}
+
+// sortNewestFirst is the in-memory equivalent of newestFirst. key returns
+// the createdAt and _id of an item.
+func sortNewestFirst[T any](items []T, key func(*T) (time.Time, primitive.ObjectID)) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		ta, ida := key(&a)
+		tb, idb := key(&b)
+		if c := compareTimes(tb, ta); c != 0 {
+			return c
+		}
+		return strings.Compare(idb.Hex(), ida.Hex())
+	})
+}
@@ -0,0 +1,306 @@
+package store
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// matchQuery evaluates a MongoDB filter against a stored document, so the
+// Mongo stores' queries can be checked without a server. It understands the
+// operators the stores build: equality (including on array elements and
+// dotted paths), $and, $or, $in, $nin, $ne, $not, $exists, $regex and the
+// range comparisons. Anything else fails the test.
+func matchQuery(t *testing.T, doc bson.Raw, query bson.M) bool {
+	t.Helper()
+	var d bson.M
+	if err := bson.Unmarshal(doc, &d); err != nil {
+		t.Fatal(err)
+	}
+	return matchDoc(t, d, normalize(t, query))
+}
+
+// normalize round-trips query through BSON, so its values have the types
+// stored documents decode to (primitive.DateTime for times, plain strings
+// for named string types and so on).
+func normalize(t *testing.T, query bson.M) bson.M {
+	t.Helper()
+	raw, err := bson.Marshal(bson.M{"q": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bson.M
+	if err := bson.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	return asDoc(t, out["q"])
+}
+
+func asDoc(t *testing.T, v interface{}) bson.M {
+	t.Helper()
+	switch d := v.(type) {
+	case bson.M:
+		return d
+	case bson.D:
+		return d.Map()
+	}
+	t.Fatalf("expected a document, got %T", v)
+	return nil
+}
+
+func matchDoc(t *testing.T, doc bson.M, query bson.M) bool {
+	t.Helper()
+	for key, cond := range query {
+		switch key {
+		case "$and", "$or":
+			clauses, ok := cond.(bson.A)
+			if !ok {
+				t.Fatalf("%s needs an array, got %T", key, cond)
+			}
+			matched := false
+			for _, clause := range clauses {
+				ok := matchDoc(t, doc, asDoc(t, clause))
+				if key == "$and" && !ok {
+					return false
+				}
+				matched = matched || ok
+			}
+			if key == "$or" && !matched {
+				return false
+			}
+		default:
+			if strings.HasPrefix(key, "$") {
+				t.Fatalf("unsupported query operator %s", key)
+			}
+			if !matchField(t, lookup(doc, strings.Split(key, ".")), cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lookup returns the values a dotted path reaches in v. Arrays are walked
+// element by element, and an array at the end of the path is returned along
+// with its elements, as MongoDB compares against both.
+func lookup(v interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		if arr, ok := v.(bson.A); ok {
+			return append([]interface{}{arr}, arr...)
+		}
+		return []interface{}{v}
+	}
+	switch d := v.(type) {
+	case bson.M:
+		child, ok := d[path[0]]
+		if !ok {
+			return nil
+		}
+		return lookup(child, path[1:])
+	case bson.D:
+		return lookup(d.Map(), path)
+	case bson.A:
+		var out []interface{}
+		for _, e := range d {
+			out = append(out, lookup(e, path)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// operators returns cond as a document of query operators, if it is one.
+func operators(cond interface{}) (bson.M, bool) {
+	var d bson.M
+	switch c := cond.(type) {
+	case bson.M:
+		d = c
+	case bson.D:
+		d = c.Map()
+	default:
+		return nil, false
+	}
+	for key := range d {
+		if !strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+	}
+	return d, len(d) > 0
+}
+
+func matchField(t *testing.T, values []interface{}, cond interface{}) bool {
+	t.Helper()
+	ops, ok := operators(cond)
+	if !ok {
+		return equalsValue(t, values, cond)
+	}
+	for op, arg := range ops {
+		if !matchOperator(t, values, op, arg) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchOperator(t *testing.T, values []interface{}, op string, arg interface{}) bool {
+	t.Helper()
+	switch op {
+	case "$exists":
+		return (len(values) > 0) == arg.(bool)
+	case "$in", "$nin":
+		in := false
+		for _, want := range arg.(bson.A) {
+			if equalsValue(t, values, want) {
+				in = true
+				break
+			}
+		}
+		return in == (op == "$in")
+	case "$ne":
+		return !equalsValue(t, values, arg)
+	case "$not":
+		return !matchField(t, values, arg)
+	case "$regex":
+		return equalsValue(t, values, arg)
+	case "$gt", "$gte", "$lt", "$lte":
+		for _, v := range values {
+			c, ok := compareValues(v, arg)
+			if !ok {
+				continue
+			}
+			if (op == "$gt" && c > 0) || (op == "$gte" && c >= 0) ||
+				(op == "$lt" && c < 0) || (op == "$lte" && c <= 0) {
+				return true
+			}
+		}
+		return false
+	}
+	t.Fatalf("unsupported field operator %s", op)
+	return false
+}
+
+// equalsValue reports whether any of values equals want. A null want also
+// matches a missing field, and a regex matches strings.
+func equalsValue(t *testing.T, values []interface{}, want interface{}) bool {
+	t.Helper()
+	if want == nil && len(values) == 0 {
+		return true
+	}
+	if re, ok := want.(primitive.Regex); ok {
+		pattern := re.Pattern
+		if strings.Contains(re.Options, "i") {
+			pattern = "(?i)" + pattern
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range values {
+			if s, ok := v.(string); ok && compiled.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, v := range values {
+		if c, ok := compareValues(v, want); ok && c == 0 {
+			return true
+		}
+		if reflect.DeepEqual(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareValues orders two values of the same BSON type, for the types the
+// stores compare.
+func compareValues(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case primitive.DateTime:
+		if y, ok := b.(primitive.DateTime); ok {
+			return compareOrdered(x, y), true
+		}
+	case primitive.ObjectID:
+		if y, ok := b.(primitive.ObjectID); ok {
+			return bytes.Compare(x[:], y[:]), true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return compareOrdered(x, y), true
+		}
+	}
+	return 0, false
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func compareOrdered[T primitive.DateTime | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func TestMatchQuery(t *testing.T) {
+	id := primitive.NewObjectID()
+	doc, err := bson.Marshal(bson.M{
+		"name":   "Black Wallet",
+		"count":  int32(3),
+		"owner":  id,
+		"tags":   bson.A{"a", "b"},
+		"images": bson.A{bson.M{"hash": "ff"}, bson.M{"url": "x"}},
+		"empty":  "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query bson.M
+		want  bool
+	}{
+		{bson.M{"name": "Black Wallet"}, true},
+		{bson.M{"name": "black wallet"}, false},
+		{bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: "wall", Options: "i"}}}, true},
+		{bson.M{"owner": id}, true},
+		{bson.M{"owner": primitive.NewObjectID()}, false},
+		{bson.M{"tags": "b"}, true},
+		{bson.M{"images.hash": bson.M{"$exists": true}}, true},
+		{bson.M{"images.caption": bson.M{"$exists": true}}, false},
+		{bson.M{"missing": bson.M{"$in": bson.A{nil, ""}}}, true},
+		{bson.M{"empty": bson.M{"$nin": bson.A{nil, ""}}}, false},
+		{bson.M{"count": bson.M{"$gte": 3, "$lt": int64(4)}}, true},
+		{bson.M{"count": bson.M{"$gt": 3}}, false},
+		{bson.M{"$or": bson.A{bson.M{"count": 1}, bson.M{"tags": "a"}}}, true},
+		{bson.M{"$and": bson.A{bson.M{"count": 3}, bson.M{"tags": "c"}}}, false},
+		{bson.M{"name": bson.M{"$not": bson.M{"$regex": primitive.Regex{Pattern: "^B"}}}}, false},
+	}
+	for _, tt := range tests {
+		if got := matchQuery(t, doc, tt.query); got != tt.want {
+			t.Errorf("matchQuery(%v) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
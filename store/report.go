@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+	"lostfound-backend/pagination"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportFilter selects a page of reports, oldest first so the queue is
+// worked in the order reports came in. Zero fields are ignored.
+type ReportFilter struct {
+	Status     models.ReportStatus
+	TargetType models.TargetType
+	Target     primitive.ObjectID
+	Limit      int64
+	Skip       int64
+}
+
+func (f ReportFilter) query() bson.M {
+	filter := bson.M{}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.TargetType != "" {
+		filter["targetType"] = f.TargetType
+	}
+	if !f.Target.IsZero() {
+		filter["target"] = f.Target
+	}
+	return filter
+}
+
+// matches is the in-memory equivalent of query.
+func (f ReportFilter) matches(report *models.Report) bool {
+	return (f.Status == "" || report.Status == f.Status) &&
+		(f.TargetType == "" || report.TargetType == f.TargetType) &&
+		(f.Target.IsZero() || report.Target == f.Target)
+}
+
+type ReportStore interface {
+	// Insert stores an open report. A reporter can only have one open
+	// report per target; a second one fails with a duplicate key error.
+	Insert(ctx context.Context, report *models.Report) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Report, error)
+	List(ctx context.Context, filter ReportFilter) ([]models.Report, error)
+	// Count returns how many reports match filter, ignoring its limit and
+	// skip.
+	Count(ctx context.Context, filter ReportFilter) (int64, error)
+	// CountOpen counts the open reports on a target, which is also the
+	// number of distinct users currently reporting it.
+	CountOpen(ctx context.Context, targetType models.TargetType, target primitive.ObjectID) (int64, error)
+	// Close gives every open report on a target status and decision, and
+	// returns how many it closed.
+	Close(ctx context.Context, targetType models.TargetType, target primitive.ObjectID, status models.ReportStatus, decision models.ModerationAction) (int64, error)
+}
+
+// AuditFilter selects a page of audit entries, newest first. Zero fields
+// are ignored.
+type AuditFilter struct {
+	Actor  primitive.ObjectID
+	Target primitive.ObjectID
+	// After continues the listing after the entry the cursor marks.
+	After *pagination.Cursor
+	Limit int64
+}
+
+func (f AuditFilter) query() bson.M {
+	filter := bson.M{}
+	if !f.Actor.IsZero() {
+		filter["actor"] = f.Actor
+	}
+	if !f.Target.IsZero() {
+		filter["target"] = f.Target
+	}
+	keysetAfter(filter, "createdAt", f.After)
+	return filter
+}
+
+// matches is the in-memory equivalent of query.
+func (f AuditFilter) matches(entry *models.AuditEntry) bool {
+	return (f.Actor.IsZero() || entry.Actor == f.Actor) &&
+		(f.Target.IsZero() || entry.Target == f.Target) &&
+		isAfter(entry.CreatedAt, entry.ID, f.After)
+}
+
+// AuditStore is append-only: entries are never changed or removed.
+type AuditStore interface {
+	Insert(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error)
+	// Count returns how many entries match filter, ignoring its cursor and
+	// limit.
+	Count(ctx context.Context, filter AuditFilter) (int64, error)
+}
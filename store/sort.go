@@ -0,0 +1,117 @@
+package store
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SortOrder orders an item listing by one field, with _id breaking ties in
+// the same direction. The zero value is the default order, newest first.
+type SortOrder struct {
+	// Field is the field's name in the API, one of LostItemSortFields or
+	// FoundItemSortFields
+	Field      string
+	Descending bool
+}
+
+// Default reports whether o is the newest-first order keyset cursors page
+// through.
+func (o SortOrder) Default() bool {
+	return o.Field == "" || (o.Field == sortCreatedAt && o.Descending)
+}
+
+const sortCreatedAt = "createdAt"
+
+// sortField is a field listings of T can be sorted on.
+type sortField[T any] struct {
+	// stored is the name the field is stored under
+	stored string
+	// compare orders two items by the field, ascending
+	compare func(a, b *T) int
+}
+
+// sortFields maps the API names of the sortable fields of T to them.
+type sortFields[T any] map[string]sortField[T]
+
+// names returns the API names of the fields, sorted.
+func (f sortFields[T]) names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// bson returns the $sort document for order, which must name one of the
+// fields.
+func (f sortFields[T]) bson(order SortOrder) bson.D {
+	if order.Field == "" {
+		order = SortOrder{Field: sortCreatedAt, Descending: true}
+	}
+	direction := 1
+	if order.Descending {
+		direction = -1
+	}
+	return bson.D{{Key: f[order.Field].stored, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// sort is the in-memory equivalent of bson. id returns the _id of an item.
+func (f sortFields[T]) sort(items []T, order SortOrder, id func(*T) primitive.ObjectID) {
+	if order.Field == "" {
+		order = SortOrder{Field: sortCreatedAt, Descending: true}
+	}
+	compare := f[order.Field].compare
+	slices.SortStableFunc(items, func(a, b T) int {
+		c := compare(&a, &b)
+		if c == 0 {
+			c = strings.Compare(id(&a).Hex(), id(&b).Hex())
+		}
+		if order.Descending {
+			return -c
+		}
+		return c
+	})
+}
+
+// compareTimes orders times at the millisecond precision MongoDB stores.
+func compareTimes(a, b time.Time) int {
+	return a.Truncate(time.Millisecond).Compare(b.Truncate(time.Millisecond))
+}
+
+var lostItemSorts = sortFields[models.LostItem]{
+	sortCreatedAt: {lostItemCreatedAt, func(a, b *models.LostItem) int { return compareTimes(a.CreatedAt, b.CreatedAt) }},
+	"updatedAt":   {lostItemUpdatedAt, func(a, b *models.LostItem) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) }},
+	"dateLost":    {lostItemDateLost, func(a, b *models.LostItem) int { return compareTimes(a.DateLost, b.DateLost) }},
+	"name":        {lostItemName, func(a, b *models.LostItem) int { return cmp.Compare(a.Name, b.Name) }},
+	"category":    {lostItemCategory, func(a, b *models.LostItem) int { return cmp.Compare(a.Category, b.Category) }},
+	"district":    {lostItemDistrict, func(a, b *models.LostItem) int { return cmp.Compare(a.District, b.District) }},
+	"state":       {lostItemState, func(a, b *models.LostItem) int { return cmp.Compare(a.State, b.State) }},
+}
+
+var foundItemSorts = sortFields[models.FoundItem]{
+	sortCreatedAt: {foundItemCreatedAt, func(a, b *models.FoundItem) int { return compareTimes(a.CreatedAt, b.CreatedAt) }},
+	"updatedAt":   {foundItemUpdatedAt, func(a, b *models.FoundItem) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) }},
+	"dateFound":   {foundItemDateFound, func(a, b *models.FoundItem) int { return compareTimes(a.DateFound, b.DateFound) }},
+	"name":        {foundItemName, func(a, b *models.FoundItem) int { return cmp.Compare(a.Name, b.Name) }},
+	"category":    {foundItemCategory, func(a, b *models.FoundItem) int { return cmp.Compare(a.Category, b.Category) }},
+	"district":    {foundItemDistrict, func(a, b *models.FoundItem) int { return cmp.Compare(a.District, b.District) }},
+	"state":       {foundItemState, func(a, b *models.FoundItem) int { return cmp.Compare(a.State, b.State) }},
+}
+
+// LostItemSortFields returns the fields lost item listings can be sorted on.
+func LostItemSortFields() []string {
+	return lostItemSorts.names()
+}
+
+// FoundItemSortFields returns the fields found item listings can be sorted on.
+func FoundItemSortFields() []string {
+	return foundItemSorts.names()
+}
@@ -0,0 +1,235 @@
+// Package store hides the MongoDB collections behind typed interfaces so the
+// route handlers can run against either a live database or an in-memory copy.
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"lostfound-backend/db"
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotFound is returned when a lookup or an owner-scoped write matches no document.
+var ErrNotFound = errors.New("document not found")
+
+// ErrConflict is returned when a versioned write finds the document changed
+// since it was read. Callers re-read it and try again.
+var ErrConflict = errors.New("document changed concurrently")
+
+// The active stores used by the route handlers. Call UseMongo or UseMemory
+// before serving requests.
+var (
+	LostItems     LostItemStore
+	FoundItems    FoundItemStore
+	Users         UserStore
+	Bookmarks     BookmarkStore
+	Matches       MatchStore
+	Claims        ClaimStore
+	Notifications NotificationStore
+	Conversations ConversationStore
+	Messages      MessageStore
+	RefreshTokens RefreshTokenStore
+	RevokedTokens RevokedTokenStore
+	OneTimeTokens OneTimeTokenStore
+	Reports       ReportStore
+	AuditLog      AuditStore
+)
+
+// UseMongo points every store at its collection in the connected database.
+func UseMongo() {
+	LostItems = &mongoLostItems{coll: db.GetCollection("lostitems")}
+	FoundItems = &mongoFoundItems{coll: db.GetCollection("founditems")}
+	Users = &mongoUsers{coll: db.GetCollection("users")}
+	Bookmarks = &mongoBookmarks{coll: db.GetCollection("bookmarks")}
+	Matches = &mongoMatches{coll: db.GetCollection("matches")}
+	Claims = &mongoClaims{coll: db.GetCollection("claims")}
+	Notifications = &mongoNotifications{coll: db.GetCollection("notifications")}
+	Conversations = &mongoConversations{coll: db.GetCollection("conversations")}
+	Messages = &mongoMessages{coll: db.GetCollection("messages")}
+	RefreshTokens = &mongoRefreshTokens{coll: db.GetCollection("refreshtokens")}
+	RevokedTokens = &mongoRevokedTokens{coll: db.GetCollection("revokedtokens")}
+	OneTimeTokens = &mongoOneTimeTokens{coll: db.GetCollection("onetimetokens")}
+	Reports = &mongoReports{coll: db.GetCollection("reports")}
+	AuditLog = &mongoAuditLog{coll: db.GetCollection("auditlog")}
+}
+
+// UseMemory points every store at a fresh, empty in-memory database.
+func UseMemory() {
+	m := NewMemory()
+	LostItems = m.LostItems()
+	FoundItems = m.FoundItems()
+	Users = m.Users()
+	Bookmarks = m.Bookmarks()
+	Matches = m.Matches()
+	Claims = m.Claims()
+	Notifications = m.Notifications()
+	Conversations = m.Conversations()
+	Messages = m.Messages()
+	RefreshTokens = m.RefreshTokens()
+	RevokedTokens = m.RevokedTokens()
+	OneTimeTokens = m.OneTimeTokens()
+	Reports = m.Reports()
+	AuditLog = m.AuditLog()
+}
+
+// GeoNear restricts a listing to items within Radius meters of Point and
+// orders it by distance, nearest first.
+type GeoNear struct {
+	Point  *models.GeoPoint
+	Radius float64
+}
+
+// stage builds the $geoNear stage for query, which must lead the pipeline.
+func (n *GeoNear) stage(query bson.M) bson.D {
+	return bson.D{{Key: "$geoNear", Value: bson.D{
+		{Key: "near", Value: n.Point},
+		{Key: "key", Value: "position"},
+		{Key: "distanceField", Value: "distance"},
+		{Key: "maxDistance", Value: n.Radius},
+		{Key: "spherical", Value: true},
+		{Key: "query", Value: query},
+	}}}
+}
+
+// within is the condition on the position field matching the same items as
+// stage, for queries such as counts that cannot use $geoNear.
+func (n *GeoNear) within() bson.M {
+	return bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{n.Point.Coordinates, n.Radius / models.EarthRadiusMeters},
+	}}
+}
+
+// distance is the in-memory equivalent of stage: it returns how far p is
+// from the search point and whether it lies inside the radius.
+func (n *GeoNear) distance(p *models.GeoPoint) (float64, bool) {
+	if p == nil || len(p.Coordinates) != 2 {
+		return 0, false
+	}
+	d := n.Point.DistanceTo(p)
+	return d, d <= n.Radius
+}
+
+// Visibility selects listed items by whether a moderator has hidden them.
+type Visibility int
+
+const (
+	VisibleItems Visibility = iota // the default: hidden items are left out
+	AllItems
+	HiddenItems
+)
+
+// query adds the condition on the hidden field to filter.
+func (v Visibility) query(filter bson.M) {
+	switch v {
+	case VisibleItems:
+		filter["hidden"] = bson.M{"$exists": false}
+	case HiddenItems:
+		filter["hidden"] = bson.M{"$exists": true}
+	}
+}
+
+// matches is the in-memory equivalent of query.
+func (v Visibility) matches(hidden *models.ModerationAction) bool {
+	switch v {
+	case VisibleItems:
+		return hidden == nil
+	case HiddenItems:
+		return hidden != nil
+	}
+	return true
+}
+
+// atVersion matches a version counter holding version. Counters are stored
+// from their first increment, so version 0 also matches a missing field.
+func atVersion(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{nil, 0}}
+	}
+	return version
+}
+
+// versionMissed tells why a versioned write to the document matching owned
+// matched nothing: ErrConflict when the document is still there, so only
+// its version moved on, and ErrNotFound otherwise.
+func versionMissed(ctx context.Context, coll *mongo.Collection, owned bson.M) error {
+	n, err := coll.CountDocuments(ctx, owned, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrConflict
+	}
+	return ErrNotFound
+}
+
+// dateRange builds an inclusive $gte/$lte condition, or nil when both bounds are zero.
+func dateRange(after, before time.Time) bson.M {
+	if after.IsZero() && before.IsZero() {
+		return nil
+	}
+	cond := bson.M{}
+	if !after.IsZero() {
+		cond["$gte"] = after
+	}
+	if !before.IsZero() {
+		cond["$lte"] = before
+	}
+	return cond
+}
+
+// equalsAny matches a field equal to one of values, ignoring case.
+func equalsAny(values ...string) bson.M {
+	patterns := bson.A{}
+	for _, value := range values {
+		patterns = append(patterns, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value) + "$", Options: "i"})
+	}
+	return bson.M{"$in": patterns}
+}
+
+// containing matches a field that contains value, ignoring case. value is
+// literal text; it is escaped rather than used as a pattern.
+func containing(value string) bson.M {
+	return bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}}
+}
+
+// contains is the in-memory equivalent of containing.
+func contains(field, value string) bool {
+	return strings.Contains(strings.ToLower(field), strings.ToLower(value))
+}
+
+// isAnyOf is the in-memory equivalent of equalsAny.
+func isAnyOf(value string, values []string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
+
+// nonEmpty matches a string field that is set to something other than "",
+// or, when set is false, one that is missing or empty.
+func nonEmpty(set bool) bson.M {
+	if set {
+		return bson.M{"$nin": bson.A{nil, ""}}
+	}
+	return bson.M{"$in": bson.A{nil, ""}}
+}
+
+// inDateRange is the in-memory equivalent of dateRange. Times are compared at
+// the millisecond precision MongoDB stores.
+func inDateRange(t, after, before time.Time) bool {
+	t = t.Truncate(time.Millisecond)
+	if !after.IsZero() && t.Before(after.Truncate(time.Millisecond)) {
+		return false
+	}
+	if !before.IsZero() && t.After(before.Truncate(time.Millisecond)) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"lostfound-backend/models"
+	"lostfound-backend/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Field weights of the text indexes. A match in an item's name counts for
+// ten times as much as the same match in its description.
+const (
+	nameWeight        = 10
+	categoryWeight    = 5
+	locationWeight    = 3
+	descriptionWeight = 1
+)
+
+// textSearch builds the $text condition for q.
+func textSearch(q *textsearch.Query) bson.M {
+	return bson.M{"$search": q.String(), "$language": textsearch.Language}
+}
+
+// textScore projects or sorts on the relevance of a $text match.
+var textScore = bson.M{"$meta": "textScore"}
+
+// lostItemText returns the fields the lostitems text index covers.
+func lostItemText(item *models.LostItem) []textsearch.Field {
+	fields := []textsearch.Field{
+		{Text: item.Name, Weight: nameWeight},
+		{Text: item.Category, Weight: categoryWeight},
+		{Text: item.Description, Weight: descriptionWeight},
+	}
+	for _, location := range item.Locations {
+		fields = append(fields, textsearch.Field{Text: location, Weight: locationWeight})
+	}
+	return fields
+}
+
+// foundItemText returns the fields the founditems text index covers.
+func foundItemText(item *models.FoundItem) []textsearch.Field {
+	return []textsearch.Field{
+		{Text: item.Name, Weight: nameWeight},
+		{Text: item.Category, Weight: categoryWeight},
+		{Text: item.LocationFound, Weight: locationWeight},
+		{Text: item.Description, Weight: descriptionWeight},
+	}
+}
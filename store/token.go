@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RefreshTokenStore interface {
+	Insert(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// Rotate marks the token used and replaced by next, then inserts next.
+	// It returns ErrNotFound if the token was already used or revoked, so
+	// two concurrent refreshes with the same token cannot both succeed.
+	Rotate(ctx context.Context, id primitive.ObjectID, next *models.RefreshToken) error
+	// RevokeFamily revokes every token of the family that is not revoked yet.
+	RevokeFamily(ctx context.Context, family primitive.ObjectID, at time.Time) error
+	// RevokeUser revokes every unrevoked token of the user and returns the
+	// families it touched.
+	RevokeUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error)
+}
+
+type RevokedTokenStore interface {
+	Revoke(ctx context.Context, revoked *models.RevokedToken) error
+	// IsRevoked reports whether an unexpired entry covers the access token
+	// with this jti or session family.
+	IsRevoked(ctx context.Context, jti string, family primitive.ObjectID) (bool, error)
+}
+
+type OneTimeTokenStore interface {
+	Insert(ctx context.Context, token *models.OneTimeToken) error
+	// Consume marks the token with this purpose and hash used and returns it.
+	// It returns ErrNotFound if there is no such token or it was already used
+	// or had expired at the given time.
+	Consume(ctx context.Context, purpose models.TokenPurpose, hash string, at time.Time) (*models.OneTimeToken, error)
+	// DeleteForUser removes the user's outstanding tokens for purpose.
+	DeleteForUser(ctx context.Context, userID primitive.ObjectID, purpose models.TokenPurpose) error
+}
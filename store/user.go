@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+
+	"lostfound-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProfileUpdate holds the user-editable profile fields. All of them are
+// written, so callers must send the complete profile. The email address is
+// changed separately, through SetPendingEmail and VerifyEmail.
+type ProfileUpdate struct {
+	Username   string
+	Phone      string
+	Profession string
+	District   string
+	State      string
+}
+
+type UserStore interface {
+	Insert(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdateProfile(ctx context.Context, id primitive.ObjectID, update ProfileUpdate) error
+	// SetPassword replaces the stored password hash.
+	SetPassword(ctx context.Context, id primitive.ObjectID, hash string) error
+	// SetPendingEmail records an address the user wants to change to. It
+	// replaces any earlier pending address.
+	SetPendingEmail(ctx context.Context, id primitive.ObjectID, email string) error
+	// VerifyEmail confirms email, which must be the user's current or pending
+	// address: it becomes the current address and is marked verified. It
+	// returns ErrNotFound if the user has neither address.
+	VerifyEmail(ctx context.Context, id primitive.ObjectID, email string) error
+	SetRole(ctx context.Context, id primitive.ObjectID, role models.Role) error
+	// SetSuspension suspends the user, or lifts the suspension when suspension is nil.
+	SetSuspension(ctx context.Context, id primitive.ObjectID, suspension *models.Suspension) error
+}
@@ -0,0 +1,110 @@
+// Package textsearch parses user search input into the MongoDB $text syntax
+// and reproduces the $text matching and scoring rules for the in-memory store.
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Language is the stemming language used by the text indexes and queries.
+const Language = "english"
+
+// Query is a parsed search string. Terms match any word, phrases must all
+// appear verbatim (case-insensitively), and excluded words or phrases reject
+// a document outright.
+type Query struct {
+	Terms           []string
+	Phrases         []string
+	ExcludedTerms   []string
+	ExcludedPhrases []string
+}
+
+// Parse splits raw user input into a Query. Words are anything made of letters
+// and digits; every other character is treated as a separator, so input can
+// never smuggle operators into the $text search string. A double-quoted run
+// is a phrase and a leading '-' negates the following word or phrase.
+func Parse(raw string) Query {
+	var q Query
+	runes := []rune(raw)
+	for i := 0; i < len(runes); {
+		negate := false
+		if runes[i] == '-' && (i == 0 || unicode.IsSpace(runes[i-1])) {
+			negate = true
+			i++
+		}
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if phrase := strings.Join(Words(string(runes[i+1:end])), " "); phrase != "" {
+				if negate {
+					q.ExcludedPhrases = append(q.ExcludedPhrases, phrase)
+				} else {
+					q.Phrases = append(q.Phrases, phrase)
+				}
+			}
+			i = end + 1
+			continue
+		}
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+			end++
+		}
+		for j, word := range Words(string(runes[i:end])) {
+			// Only the first word of a hyphenated token is negated
+			if negate && j == 0 {
+				q.ExcludedTerms = append(q.ExcludedTerms, word)
+			} else {
+				q.Terms = append(q.Terms, word)
+			}
+		}
+		if end == i {
+			end++
+		}
+		i = end
+	}
+	return q
+}
+
+// Empty reports whether q has nothing that could match a document. MongoDB
+// returns no results for a search made only of exclusions or stop words.
+func (q Query) Empty() bool {
+	for _, t := range q.Terms {
+		if !IsStopWord(t) {
+			return false
+		}
+	}
+	for _, p := range q.Phrases {
+		for _, w := range strings.Fields(p) {
+			if !IsStopWord(w) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String renders q as a $text $search string.
+func (q Query) String() string {
+	var parts []string
+	parts = append(parts, q.Terms...)
+	for _, p := range q.Phrases {
+		parts = append(parts, `"`+p+`"`)
+	}
+	for _, t := range q.ExcludedTerms {
+		parts = append(parts, "-"+t)
+	}
+	for _, p := range q.ExcludedPhrases {
+		parts = append(parts, `-"`+p+`"`)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Words lowercases s and splits it into runs of letters and digits.
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
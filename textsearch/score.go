@@ -0,0 +1,108 @@
+package textsearch
+
+import (
+	"math"
+	"strings"
+)
+
+// Field is one indexed string value and the weight its field carries in the
+// text index. Array fields contribute one Field per element.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Score reports whether a document made of fields satisfies q and, if so, its
+// relevance. Matching follows $text: at least one term must be present after
+// stemming, every phrase must appear within a single field, and any excluded
+// word or phrase rules the document out. The score uses the same per-field
+// term frequency formula as MongoDB, so results rank the way the Mongo store
+// ranks them.
+func Score(q Query, fields []Field) (float64, bool) {
+	for _, p := range q.ExcludedPhrases {
+		if containsPhrase(fields, p) {
+			return 0, false
+		}
+	}
+	for _, p := range q.Phrases {
+		if !containsPhrase(fields, p) {
+			return 0, false
+		}
+	}
+
+	excluded := map[string]bool{}
+	for _, t := range q.ExcludedTerms {
+		if !IsStopWord(t) {
+			excluded[Stem(t)] = true
+		}
+	}
+	wanted := map[string]bool{}
+	for _, t := range q.Terms {
+		if !IsStopWord(t) {
+			wanted[Stem(t)] = true
+		}
+	}
+	for _, p := range q.Phrases {
+		for _, w := range strings.Fields(p) {
+			if !IsStopWord(w) {
+				wanted[Stem(w)] = true
+			}
+		}
+	}
+
+	score, matched := 0.0, false
+	for _, f := range fields {
+		for term, s := range fieldScores(f) {
+			if excluded[term] {
+				return 0, false
+			}
+			if wanted[term] {
+				score += s
+				matched = true
+			}
+		}
+	}
+	return score, matched
+}
+
+func containsPhrase(fields []Field, phrase string) bool {
+	for _, f := range fields {
+		if strings.Contains(strings.Join(Words(f.Text), " "), phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldScores returns the score each stemmed term earns in f. Repeated
+// occurrences count for less each time, and the result is scaled by how much
+// of the field the term makes up.
+func fieldScores(f Field) map[string]float64 {
+	type termData struct {
+		freq  float64
+		count int
+	}
+	terms := map[string]*termData{}
+	tokens := 0
+	for _, w := range Words(f.Text) {
+		if IsStopWord(w) {
+			continue
+		}
+		stem := Stem(w)
+		data, ok := terms[stem]
+		if !ok {
+			data = &termData{}
+			terms[stem] = data
+		}
+		data.freq += 1 / math.Pow(2, float64(data.count))
+		data.count++
+		tokens++
+	}
+
+	scores := make(map[string]float64, len(terms))
+	for term, data := range terms {
+		coeff := 0.5*float64(data.count)/float64(tokens) + 0.5
+		scores[term] = f.Weight * data.freq * coeff
+	}
+	return scores
+}
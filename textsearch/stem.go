@@ -0,0 +1,281 @@
+package textsearch
+
+import "strings"
+
+// Stem reduces a lowercase English word to its stem using the Snowball
+// English (Porter2) algorithm, the stemmer MongoDB's text indexes use.
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if stem, ok := stemExceptions[word]; ok {
+		return stem
+	}
+
+	w := []byte(strings.TrimPrefix(word, "'"))
+	if len(w) == 0 {
+		return word
+	}
+
+	// Mark consonant y's so they are not treated as vowels
+	if w[0] == 'y' {
+		w[0] = 'Y'
+	}
+	for i := 1; i < len(w); i++ {
+		if w[i] == 'y' && isVowel(w[i-1]) {
+			w[i] = 'Y'
+		}
+	}
+
+	r1, r2 := regions(w)
+
+	w = step0(w)
+	w = step1a(w)
+	if _, ok := postStep1aInvariants[string(w)]; ok {
+		return string(w)
+	}
+	w = step1b(w, r1)
+	w = step1c(w)
+	w = step2(w, r1)
+	w = step3(w, r1, r2)
+	w = step4(w, r2)
+	w = step5(w, r1, r2)
+
+	return strings.ReplaceAll(string(w), "Y", "y")
+}
+
+var stemExceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli",
+	"singly": "singl", "sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas",
+	"cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+var postStep1aInvariants = map[string]struct{}{
+	"inning": {}, "outing": {}, "canning": {}, "herring": {}, "earring": {},
+	"proceed": {}, "exceed": {}, "succeed": {},
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// regions returns the start offsets of R1 and R2.
+func regions(w []byte) (int, int) {
+	r1 := len(w)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(w), prefix) {
+			r1 = len(prefix)
+			break
+		}
+	}
+	if r1 == len(w) {
+		r1 = regionAfter(w, 0)
+	}
+	return r1, regionAfter(w, r1)
+}
+
+// regionAfter returns the offset after the first non-vowel that follows a
+// vowel, searching from start.
+func regionAfter(w []byte, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isVowel(w[i]) && isVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+func hasSuffix(w []byte, suffix string) bool {
+	return strings.HasSuffix(string(w), suffix)
+}
+
+// longestSuffix returns the longest of suffixes that w ends with, or "".
+func longestSuffix(w []byte, suffixes ...string) string {
+	best := ""
+	for _, s := range suffixes {
+		if len(s) > len(best) && hasSuffix(w, s) {
+			best = s
+		}
+	}
+	return best
+}
+
+func replaceSuffix(w []byte, suffix, with string) []byte {
+	return append(w[:len(w)-len(suffix)], with...)
+}
+
+func containsVowel(w []byte) bool {
+	for _, c := range w {
+		if isVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsShortSyllable reports whether w ends in a short syllable.
+func endsShortSyllable(w []byte) bool {
+	n := len(w)
+	if n == 2 {
+		return isVowel(w[0]) && !isVowel(w[1])
+	}
+	if n >= 3 {
+		c := w[n-1]
+		return !isVowel(w[n-3]) && isVowel(w[n-2]) && !isVowel(c) && c != 'w' && c != 'x' && c != 'Y'
+	}
+	return false
+}
+
+func isShortWord(w []byte, r1 int) bool {
+	return r1 >= len(w) && endsShortSyllable(w)
+}
+
+func step0(w []byte) []byte {
+	if s := longestSuffix(w, "'", "'s", "'s'"); s != "" {
+		return w[:len(w)-len(s)]
+	}
+	return w
+}
+
+func step1a(w []byte) []byte {
+	switch longestSuffix(w, "sses", "ied", "ies", "us", "ss", "s") {
+	case "sses":
+		return replaceSuffix(w, "sses", "ss")
+	case "ied", "ies":
+		if len(w) > 4 {
+			return replaceSuffix(w, string(w[len(w)-3:]), "i")
+		}
+		return replaceSuffix(w, string(w[len(w)-3:]), "ie")
+	case "s":
+		// Delete if a vowel occurs before the letter preceding the s
+		if len(w) >= 3 && containsVowel(w[:len(w)-2]) {
+			return w[:len(w)-1]
+		}
+	}
+	return w
+}
+
+func step1b(w []byte, r1 int) []byte {
+	switch s := longestSuffix(w, "eed", "eedly", "ed", "edly", "ing", "ingly"); s {
+	case "eed", "eedly":
+		if len(w)-len(s) >= r1 {
+			return replaceSuffix(w, s, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		stem := w[:len(w)-len(s)]
+		if !containsVowel(stem) {
+			return w
+		}
+		w = stem
+		switch {
+		case hasSuffix(w, "at"), hasSuffix(w, "bl"), hasSuffix(w, "iz"):
+			return append(w, 'e')
+		case endsWithDouble(w):
+			return w[:len(w)-1]
+		case isShortWord(w, r1):
+			return append(w, 'e')
+		}
+	}
+	return w
+}
+
+func endsWithDouble(w []byte) bool {
+	for _, d := range []string{"bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt"} {
+		if hasSuffix(w, d) {
+			return true
+		}
+	}
+	return false
+}
+
+func step1c(w []byte) []byte {
+	n := len(w)
+	if n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !isVowel(w[n-2]) {
+		w[n-1] = 'i'
+	}
+	return w
+}
+
+var step2Suffixes = map[string]string{
+	"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able", "entli": "ent",
+	"izer": "ize", "ization": "ize", "ational": "ate", "ation": "ate", "ator": "ate",
+	"alism": "al", "aliti": "al", "alli": "al", "fulness": "ful", "ousli": "ous",
+	"ousness": "ous", "iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble",
+	"ogi": "og", "fulli": "ful", "lessli": "less", "li": "",
+}
+
+func step2(w []byte, r1 int) []byte {
+	suffixes := make([]string, 0, len(step2Suffixes))
+	for s := range step2Suffixes {
+		suffixes = append(suffixes, s)
+	}
+	s := longestSuffix(w, suffixes...)
+	if s == "" || len(w)-len(s) < r1 {
+		return w
+	}
+	switch s {
+	case "ogi":
+		if len(w) < 4 || w[len(w)-4] != 'l' {
+			return w
+		}
+	case "li":
+		if len(w) < 3 || !strings.ContainsRune("cdeghkmnrt", rune(w[len(w)-3])) {
+			return w
+		}
+	}
+	return replaceSuffix(w, s, step2Suffixes[s])
+}
+
+var step3Suffixes = map[string]string{
+	"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic", "iciti": "ic",
+	"ical": "ic", "ful": "", "ness": "", "ative": "",
+}
+
+func step3(w []byte, r1, r2 int) []byte {
+	suffixes := make([]string, 0, len(step3Suffixes))
+	for s := range step3Suffixes {
+		suffixes = append(suffixes, s)
+	}
+	s := longestSuffix(w, suffixes...)
+	if s == "" || len(w)-len(s) < r1 {
+		return w
+	}
+	if s == "ative" && len(w)-len(s) < r2 {
+		return w
+	}
+	return replaceSuffix(w, s, step3Suffixes[s])
+}
+
+func step4(w []byte, r2 int) []byte {
+	s := longestSuffix(w, "al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement",
+		"ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion")
+	if s == "" || len(w)-len(s) < r2 {
+		return w
+	}
+	if s == "ion" {
+		if len(w) < 4 || (w[len(w)-4] != 's' && w[len(w)-4] != 't') {
+			return w
+		}
+	}
+	return w[:len(w)-len(s)]
+}
+
+func step5(w []byte, r1, r2 int) []byte {
+	n := len(w)
+	switch {
+	case n > 0 && w[n-1] == 'e':
+		if n-1 >= r2 || (n-1 >= r1 && !endsShortSyllable(w[:n-1])) {
+			return w[:n-1]
+		}
+	case n > 1 && w[n-1] == 'l' && w[n-2] == 'l':
+		if n-1 >= r2 {
+			return w[:n-1]
+		}
+	}
+	return w
+}
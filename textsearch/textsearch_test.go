@@ -0,0 +1,120 @@
+package textsearch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Query
+	}{
+		{"black wallet", Query{Terms: []string{"black", "wallet"}}},
+		{`"red phone" case`, Query{Terms: []string{"case"}, Phrases: []string{"red phone"}}},
+		{`wallet -leather -"car keys"`, Query{Terms: []string{"wallet"}, ExcludedTerms: []string{"leather"}, ExcludedPhrases: []string{"car keys"}}},
+		{"well-known", Query{Terms: []string{"well", "known"}}},
+		{"-well-known", Query{Terms: []string{"known"}, ExcludedTerms: []string{"well"}}},
+		{`$where: {"x": 1}`, Query{Terms: []string{"where", "1"}, Phrases: []string{"x"}}},
+		{`"unterminated phrase`, Query{Phrases: []string{"unterminated phrase"}}},
+		{"iPhone 13 PRO", Query{Terms: []string{"iphone", "13", "pro"}}},
+		{"", Query{}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.raw); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	q := Parse(`wallet "red phone" -leather -"car keys"`)
+	if got, want := q.String(), `wallet "red phone" -leather -"car keys"`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestQueryEmpty(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want bool
+	}{
+		{"wallet", false},
+		{`"the wallet"`, false},
+		{"the and of", true},
+		{"-wallet", true},
+		{`"the of"`, true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.raw).Empty(); got != tt.want {
+			t.Errorf("Parse(%q).Empty() = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"wallets":    "wallet",
+		"keys":       "key",
+		"running":    "run",
+		"hopping":    "hop",
+		"caresses":   "caress",
+		"ponies":     "poni",
+		"connection": "connect",
+		"generously": "generous",
+		"happiness":  "happi",
+		"skies":      "sky",
+		"news":       "news",
+		"at":         "at",
+	}
+	for word, want := range tests {
+		if got := Stem(word); got != want {
+			t.Errorf("Stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	fields := []Field{
+		{Text: "Black leather wallet", Weight: 10},
+		{Text: "Lost near the central station with my car keys", Weight: 1},
+	}
+	tests := []struct {
+		raw   string
+		match bool
+	}{
+		{"wallet", true},
+		{"wallets", true},
+		{"purse wallet", true},
+		{"purse", false},
+		{`"leather wallet"`, true},
+		{`"wallet leather"`, false},
+		{`"leather wallet" purse`, true},
+		// A phrase has to appear within one field
+		{`"wallet lost"`, false},
+		{"wallet -keys", false},
+		{`wallet -"house keys"`, true},
+		{`wallet -"car keys"`, false},
+	}
+	for _, tt := range tests {
+		if _, ok := Score(Parse(tt.raw), fields); ok != tt.match {
+			t.Errorf("Score(%q) match = %v, want %v", tt.raw, ok, tt.match)
+		}
+	}
+}
+
+func TestScoreWeights(t *testing.T) {
+	q := Parse("wallet")
+	inName, _ := Score(q, []Field{{Text: "wallet", Weight: 10}, {Text: "found a thing", Weight: 1}})
+	inDescription, _ := Score(q, []Field{{Text: "thing", Weight: 10}, {Text: "found a wallet", Weight: 1}})
+	if inName <= inDescription {
+		t.Errorf("match in the heavier field scored %v, not above %v", inName, inDescription)
+	}
+
+	once, _ := Score(q, []Field{{Text: "wallet case", Weight: 1}})
+	twice, _ := Score(q, []Field{{Text: "wallet wallet case", Weight: 1}})
+	if twice <= once {
+		t.Errorf("repeated term scored %v, not above %v", twice, once)
+	}
+}
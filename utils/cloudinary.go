@@ -1,47 +0,0 @@
-package utils
-
-import (
-	"context"
-	"fmt"
-	"log"
-	"mime/multipart"
-	"os"
-
-	"github.com/cloudinary/cloudinary-go/v2"
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
-)
-
-var cld *cloudinary.Cloudinary
-
-func InitCloudinary() {
-	var err error
-	cld, err = cloudinary.NewFromParams(
-		os.Getenv("CLOUDINARY_CLOUD_NAME"),
-		os.Getenv("CLOUDINARY_API_KEY"),
-		os.Getenv("CLOUDINARY_API_SECRET"),
-	)
-	if err != nil {
-		log.Fatalf("Failed to initialize Cloudinary: %v", err)
-	}
-}
-
-func UploadImage(fileHeader *multipart.FileHeader) (string, error) {
-	file, err := fileHeader.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %v", err)
-	}
-	defer file.Close()
-
-	ctx := context.Background()
-	uploadResult, err := cld.Upload.Upload(
-		ctx,
-		file,
-		uploader.UploadParams{
-			Folder: "lostfound",
-		})
-	if err != nil {
-		return "", fmt.Errorf("failed to upload image: %v", err)
-	}
-
-	return uploadResult.SecureURL, nil
-}
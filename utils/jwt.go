@@ -11,10 +11,31 @@ import (
 
 var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
 
-func GenerateJWT(userID string) (string, error) {
+const (
+	// AccessTokenTTL is kept short so a leaked access token is only useful
+	// briefly; clients renew it with their refresh token.
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// GenerateJWT issues an access token for userID. sessionID names the refresh
+// token family the token belongs to, so logging out or detecting refresh
+// token reuse can revoke every access token of the session at once. role is
+// the user's role when the token is issued; a role change reaches the
+// client's token on its next refresh.
+func GenerateJWT(userID, sessionID, role string) (string, error) {
+	jti, err := RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"userId": userID,
-		"exp":    time.Now().Add(time.Hour * 72).Unix(),
+		"sid":    sessionID,
+		"role":   role,
+		"jti":    jti,
+		"iat":    now.Unix(),
+		"exp":    now.Add(AccessTokenTTL).Unix(),
 	})
 	return token.SignedString(jwtSecret)
 }